@@ -0,0 +1,161 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package arbitrage finds surebets and middles by comparing the best prices
+// across bookmakers on the same event and market.
+package arbitrage
+
+import (
+	"fmt"
+	"oddsapi"
+	"sort"
+	"time"
+)
+
+type Options struct {
+	// OddsFormat the prices were requested in. Defaults to decimal.
+	OddsFormat oddsapi.OddsFormat
+
+	// Bankroll is split across the legs of each opportunity
+	Bankroll float64
+
+	// MinROI filters out opportunities below this return, e.g. 0.01 for 1%
+	MinROI float64
+
+	// MaxAge marks a leg stale when its market was last updated longer
+	// ago than this. Zero disables staleness checks.
+	MaxAge time.Duration
+
+	// Now is used for staleness checks. Defaults to time.Now.
+	Now func() time.Time
+}
+
+func (o *Options) validate() error {
+	if o.OddsFormat != "" && !o.OddsFormat.Valid() {
+		return fmt.Errorf("invalid odds format: %s", o.OddsFormat)
+	}
+	if o.Now == nil {
+		o.Now = time.Now
+	}
+	return nil
+}
+
+type Leg struct {
+	BookMaker      string
+	BookMakerTitle string
+	Outcome        string
+	Point          *float64
+	Price          float64
+	DecimalPrice   float64
+	Stake          float64
+	Payout         float64
+	LastUpdate     time.Time
+	Stale          bool
+}
+
+type Opportunity struct {
+	EventId      string
+	SportKey     string
+	HomeTeam     string
+	AwayTeam     string
+	CommenceTime string
+	Market       string
+
+	// Line is the home team's spread or the total for points markets
+	Line *float64
+
+	Legs []*Leg
+
+	// ImpliedTotal is the sum of the implied probabilities of the best
+	// price on each outcome. Anything below 1 is a surebet.
+	ImpliedTotal float64
+	ROI          float64
+	Profit       float64
+
+	// Age of the oldest leg. Zero when no leg has a parsable last update.
+	Age   time.Duration
+	Stale bool
+}
+
+// Scan finds surebets across bookmakers for every event and market in odds.
+// Results are sorted by ROI, highest first.
+func Scan(odds []*oddsapi.Odds, opts Options) ([]*Opportunity, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	now := opts.Now()
+
+	var result []*Opportunity
+	for _, event := range odds {
+		if event == nil {
+			continue
+		}
+		for _, l := range collectLines(event, opts.OddsFormat) {
+			if !l.complete() {
+				continue
+			}
+			o := newOpportunity(event, l, opts, now)
+			if o.ImpliedTotal < 1 && o.ROI >= opts.MinROI {
+				result = append(result, o)
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ROI > result[j].ROI
+	})
+	return result, nil
+}
+
+func newOpportunity(event *oddsapi.Odds, l *line, opts Options, now time.Time) *Opportunity {
+	o := &Opportunity{
+		EventId:      event.Id,
+		SportKey:     event.SportKey,
+		HomeTeam:     event.HomeTeam,
+		AwayTeam:     event.AwayTeam,
+		CommenceTime: event.CommenceTime,
+		Market:       l.market,
+		Line:         l.point,
+	}
+
+	quotes := make([]*quote, len(l.order))
+	for i, side := range l.order {
+		quotes[i] = l.best(side)
+	}
+	o.Legs, o.ImpliedTotal, o.Age, o.Stale = newLegs(quotes, opts, now)
+	o.ROI = 1/o.ImpliedTotal - 1
+	o.Profit = opts.Bankroll * o.ROI
+	return o
+}
+
+// newLegs builds one leg per quote with stakes sized so that every leg
+// returns the same payout.
+func newLegs(quotes []*quote, opts Options, now time.Time) (legs []*Leg, impliedTotal float64, age time.Duration, stale bool) {
+	for _, q := range quotes {
+		impliedTotal += oddsapi.ImpliedProbability(q.decimal)
+		leg := &Leg{
+			BookMaker:      q.bookMaker.Key,
+			BookMakerTitle: q.bookMaker.Title,
+			Outcome:        q.outcome.Name,
+			Point:          q.outcome.Point,
+			Price:          q.outcome.Price,
+			DecimalPrice:   q.decimal,
+			LastUpdate:     q.lastUpdate,
+		}
+		if !q.lastUpdate.IsZero() {
+			a := now.Sub(q.lastUpdate)
+			if a > age {
+				age = a
+			}
+			leg.Stale = opts.MaxAge > 0 && a > opts.MaxAge
+		}
+		stale = stale || leg.Stale
+		legs = append(legs, leg)
+	}
+
+	for _, leg := range legs {
+		leg.Stake = opts.Bankroll * oddsapi.ImpliedProbability(leg.DecimalPrice) / impliedTotal
+		leg.Payout = leg.Stake * leg.DecimalPrice
+	}
+	return legs, impliedTotal, age, stale
+}
@@ -0,0 +1,195 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package arbitrage
+
+import (
+	"math"
+	"oddsapi"
+	"testing"
+	"time"
+)
+
+func point(p float64) *float64 {
+	return &p
+}
+
+func bookMaker(key, lastUpdate string, markets ...*oddsapi.Market) *oddsapi.BookMaker {
+	for _, m := range markets {
+		m.LastUpdate = lastUpdate
+	}
+	return &oddsapi.BookMaker{Key: key, Title: key, Markets: markets}
+}
+
+func market(key string, outcomes ...*oddsapi.Outcome) *oddsapi.Market {
+	return &oddsapi.Market{Key: key, Outcomes: outcomes}
+}
+
+func event(books ...*oddsapi.BookMaker) *oddsapi.Odds {
+	return &oddsapi.Odds{
+		Id:         "event1",
+		SportKey:   "basketball_nba",
+		HomeTeam:   "Home",
+		AwayTeam:   "Away",
+		BookMakers: books,
+	}
+}
+
+const updated = "2024-01-01T12:00:00Z"
+
+func TestScan_H2HTwoWay(t *testing.T) {
+	odds := []*oddsapi.Odds{event(
+		bookMaker("book1", updated, market("h2h",
+			&oddsapi.Outcome{Name: "Home", Price: 2.1},
+			&oddsapi.Outcome{Name: "Away", Price: 1.7})),
+		bookMaker("book2", updated, market("h2h",
+			&oddsapi.Outcome{Name: "Home", Price: 1.8},
+			&oddsapi.Outcome{Name: "Away", Price: 2.05})),
+	)}
+
+	result, err := Scan(odds, Options{Bankroll: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 opportunity, got %d", len(result))
+	}
+
+	o := result[0]
+	expectedTotal := 1/2.1 + 1/2.05
+	if math.Abs(o.ImpliedTotal-expectedTotal) > 1e-9 {
+		t.Errorf("expected implied total %v, got %v", expectedTotal, o.ImpliedTotal)
+	}
+	if o.Legs[0].BookMaker != "book1" || o.Legs[1].BookMaker != "book2" {
+		t.Errorf("expected best prices at book1 and book2, got %s and %s", o.Legs[0].BookMaker, o.Legs[1].BookMaker)
+	}
+	if math.Abs(o.Legs[0].Payout-o.Legs[1].Payout) > 1e-9 {
+		t.Errorf("expected equal payouts, got %v and %v", o.Legs[0].Payout, o.Legs[1].Payout)
+	}
+	if math.Abs(o.Legs[0].Stake+o.Legs[1].Stake-100) > 1e-9 {
+		t.Errorf("expected stakes to sum to the bankroll")
+	}
+	if math.Abs(o.Legs[0].Payout-100-o.Profit) > 1e-9 {
+		t.Errorf("expected profit %v, got %v", o.Legs[0].Payout-100, o.Profit)
+	}
+}
+
+func TestScan_H2HThreeWayRequiresAllOutcomes(t *testing.T) {
+	odds := []*oddsapi.Odds{event(
+		bookMaker("book1", updated, market("h2h",
+			&oddsapi.Outcome{Name: "Home", Price: 3.1},
+			&oddsapi.Outcome{Name: "Away", Price: 3.6},
+			&oddsapi.Outcome{Name: "Draw", Price: 3.3})),
+		bookMaker("book2", updated, market("h2h",
+			&oddsapi.Outcome{Name: "Home", Price: 2.8},
+			&oddsapi.Outcome{Name: "Away", Price: 2.4})),
+	)}
+
+	result, err := Scan(odds, Options{Bankroll: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 opportunity, got %d", len(result))
+	}
+	if len(result[0].Legs) != 3 {
+		t.Errorf("expected 3 legs, got %d", len(result[0].Legs))
+	}
+
+	// Without the draw the two-way prices would look like an arb
+	odds[0].BookMakers = odds[0].BookMakers[1:]
+	odds[0].BookMakers[0].Markets[0].Outcomes[0].Price = 3.1
+	odds[0].BookMakers[0].Markets[0].Outcomes[1].Price = 3.6
+	odds[0].BookMakers = append(odds[0].BookMakers, bookMaker("book3", updated, market("h2h",
+		&oddsapi.Outcome{Name: "Home", Price: 2},
+		&oddsapi.Outcome{Name: "Away", Price: 2},
+		&oddsapi.Outcome{Name: "Draw", Price: 2.5})))
+	result, err = Scan(odds, Options{Bankroll: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 0 {
+		t.Errorf("expected no opportunity, got %d", len(result))
+	}
+}
+
+func TestScan_SpreadsMatchOpposingPoints(t *testing.T) {
+	odds := []*oddsapi.Odds{event(
+		bookMaker("book1", updated, market("spreads",
+			&oddsapi.Outcome{Name: "Home", Price: 2.1, Point: point(-3.5)},
+			&oddsapi.Outcome{Name: "Away", Price: 1.75, Point: point(3.5)})),
+		bookMaker("book2", updated, market("spreads",
+			&oddsapi.Outcome{Name: "Home", Price: 1.8, Point: point(-3.5)},
+			&oddsapi.Outcome{Name: "Away", Price: 2.1, Point: point(3.5)})),
+		bookMaker("book3", updated, market("spreads",
+			&oddsapi.Outcome{Name: "Home", Price: 2.5, Point: point(-4.5)},
+			&oddsapi.Outcome{Name: "Away", Price: 1.5, Point: point(4.5)})),
+	)}
+
+	result, err := Scan(odds, Options{Bankroll: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 opportunity, got %d", len(result))
+	}
+	if *result[0].Line != -3.5 {
+		t.Errorf("expected line -3.5, got %v", *result[0].Line)
+	}
+	if *result[0].Legs[1].Point != 3.5 {
+		t.Errorf("expected away leg at +3.5, got %v", *result[0].Legs[1].Point)
+	}
+}
+
+func TestScan_OutrightsAmericanAndStale(t *testing.T) {
+	odds := []*oddsapi.Odds{event(
+		bookMaker("book1", "2024-01-01T11:00:00Z", market("outrights",
+			&oddsapi.Outcome{Name: "A", Price: 250},
+			&oddsapi.Outcome{Name: "B", Price: 200},
+			&oddsapi.Outcome{Name: "C", Price: 150})),
+		bookMaker("book2", updated, market("outrights",
+			&oddsapi.Outcome{Name: "A", Price: 150},
+			&oddsapi.Outcome{Name: "B", Price: 350},
+			&oddsapi.Outcome{Name: "C", Price: 120})),
+	)}
+
+	now := func() time.Time {
+		return time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)
+	}
+	result, err := Scan(odds, Options{
+		OddsFormat: oddsapi.AmericanOddsFormat,
+		Bankroll:   100,
+		MaxAge:     10 * time.Minute,
+		Now:        now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 opportunity, got %d", len(result))
+	}
+
+	o := result[0]
+	if len(o.Legs) != 3 {
+		t.Fatalf("expected 3 legs, got %d", len(o.Legs))
+	}
+	if !o.Stale {
+		t.Error("expected opportunity to be stale")
+	}
+	if o.Age != 65*time.Minute {
+		t.Errorf("expected age of 65m, got %s", o.Age)
+	}
+	if o.Legs[1].Stale || o.Legs[1].BookMaker != "book2" {
+		t.Errorf("expected fresh leg at book2 for B")
+	}
+	if o.Legs[0].DecimalPrice != 3.5 {
+		t.Errorf("expected decimal price 3.5, got %v", o.Legs[0].DecimalPrice)
+	}
+}
+
+func TestScan_InvalidOddsFormat(t *testing.T) {
+	_, err := Scan(nil, Options{OddsFormat: "fractional"})
+	if err == nil {
+		t.Error("expected an error for an invalid odds format")
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package arbitrage
+
+import (
+	"oddsapi"
+	"sort"
+	"time"
+)
+
+// quote is a single bookmaker price for an outcome, converted to decimal odds.
+type quote struct {
+	bookMaker  *oddsapi.BookMaker
+	outcome    *oddsapi.Outcome
+	decimal    float64
+	lastUpdate time.Time
+}
+
+// line groups every bookmaker quote on one market of one event that can be
+// compared directly, keyed by the home team's point for spreads.
+type line struct {
+	market   string
+	point    *float64
+	expected int
+	sides    map[string][]*quote
+	order    []string
+}
+
+func (l *line) best(side string) *quote {
+	var b *quote
+	for _, q := range l.sides[side] {
+		if b == nil || q.decimal > b.decimal {
+			b = q
+		}
+	}
+	return b
+}
+
+func (l *line) complete() bool {
+	return len(l.sides) >= 2 && len(l.sides) >= l.expected
+}
+
+func collectLines(event *oddsapi.Odds, format oddsapi.OddsFormat) []*line {
+	lines := make(map[string]*line)
+	var keys []string
+	for _, b := range event.BookMakers {
+		for _, m := range b.Markets {
+			updated, _ := m.LastUpdateTime()
+			counts := make(map[string]int)
+			for _, o := range m.Outcomes {
+				decimal, err := format.ToDecimal(o.Price)
+				if err != nil {
+					continue
+				}
+				point := event.LinePoint(m.Key, o)
+				key := event.LineKey(m.Key, o)
+				l, ok := lines[key]
+				if !ok {
+					l = &line{market: m.Key, point: point, sides: make(map[string][]*quote)}
+					lines[key] = l
+					keys = append(keys, key)
+				}
+				if _, ok := l.sides[o.Name]; !ok {
+					l.order = append(l.order, o.Name)
+				}
+				l.sides[o.Name] = append(l.sides[o.Name], &quote{
+					bookMaker:  b,
+					outcome:    o,
+					decimal:    decimal,
+					lastUpdate: updated,
+				})
+				counts[key]++
+			}
+			for key, n := range counts {
+				if n > lines[key].expected {
+					lines[key].expected = n
+				}
+			}
+		}
+	}
+	sort.Strings(keys)
+	result := make([]*line, len(keys))
+	for i, key := range keys {
+		result[i] = lines[key]
+	}
+	return result
+}
@@ -0,0 +1,139 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package arbitrage
+
+import (
+	"math"
+	"oddsapi"
+	"sort"
+	"time"
+)
+
+const (
+	overOutcome  = "Over"
+	underOutcome = "Under"
+)
+
+type Middle struct {
+	EventId      string
+	SportKey     string
+	HomeTeam     string
+	AwayTeam     string
+	CommenceTime string
+	Market       string
+
+	// Legs holds the low side first: the home team for spreads and the
+	// over for totals.
+	Legs []*Leg
+
+	// Low and High bound the window of final results where both legs win.
+	// For spreads the result is the home score minus the away score, for
+	// totals it is the combined score.
+	Low   float64
+	High  float64
+	Width float64
+
+	// Pushes lists integer window bounds where one leg pushes and the
+	// other wins.
+	Pushes []float64
+
+	// Hold is the combined overround of the two legs. With stakes sized
+	// for equal payouts it is also the break-even hit rate of the window.
+	Hold             float64
+	BreakEvenHitRate float64
+
+	Age   time.Duration
+	Stale bool
+}
+
+// FindMiddles pairs opposing spreads and totals outcomes at different points
+// across bookmakers where a final result inside the window wins both legs.
+// Results are sorted by window width, widest first, then by hold.
+func FindMiddles(odds []*oddsapi.Odds, opts Options) ([]*Middle, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	now := opts.Now()
+
+	var result []*Middle
+	for _, event := range odds {
+		if event == nil {
+			continue
+		}
+		var spreads, totals []*line
+		for _, l := range collectLines(event, opts.OddsFormat) {
+			if l.point == nil {
+				continue
+			}
+			switch l.market {
+			case oddsapi.MarketSpreads.String():
+				spreads = append(spreads, l)
+			case oddsapi.MarketTotals.String():
+				totals = append(totals, l)
+			}
+		}
+		result = append(result, findMiddles(event, spreads, event.HomeTeam, event.AwayTeam, opts, now)...)
+		result = append(result, findMiddles(event, totals, overOutcome, underOutcome, opts, now)...)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Width != result[j].Width {
+			return result[i].Width > result[j].Width
+		}
+		return result[i].Hold < result[j].Hold
+	})
+	return result, nil
+}
+
+// findMiddles pairs the low side of one line with the high side of another.
+// Spread lines are normalized to the home team, so home at a line above the
+// away team's line wins both when the margin lands in between.
+func findMiddles(event *oddsapi.Odds, lines []*line, lowSide, highSide string, opts Options, now time.Time) []*Middle {
+	var result []*Middle
+	for _, a := range lines {
+		low := a.best(lowSide)
+		if low == nil {
+			continue
+		}
+		for _, b := range lines {
+			high := b.best(highSide)
+			if high == nil {
+				continue
+			}
+
+			var lo, hi float64
+			if a.market == oddsapi.MarketSpreads.String() {
+				lo, hi = -*a.point, -*b.point
+			} else {
+				lo, hi = *a.point, *b.point
+			}
+			if hi <= lo {
+				continue
+			}
+
+			m := &Middle{
+				EventId:      event.Id,
+				SportKey:     event.SportKey,
+				HomeTeam:     event.HomeTeam,
+				AwayTeam:     event.AwayTeam,
+				CommenceTime: event.CommenceTime,
+				Market:       a.market,
+				Low:          lo,
+				High:         hi,
+				Width:        hi - lo,
+			}
+			var impliedTotal float64
+			m.Legs, impliedTotal, m.Age, m.Stale = newLegs([]*quote{low, high}, opts, now)
+			m.Hold = impliedTotal - 1
+			m.BreakEvenHitRate = math.Max(m.Hold, 0)
+			for _, bound := range []float64{lo, hi} {
+				if bound == math.Trunc(bound) {
+					m.Pushes = append(m.Pushes, bound)
+				}
+			}
+			result = append(result, m)
+		}
+	}
+	return result
+}
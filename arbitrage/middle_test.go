@@ -0,0 +1,100 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package arbitrage
+
+import (
+	"math"
+	"oddsapi"
+	"testing"
+)
+
+func TestFindMiddles_Spreads(t *testing.T) {
+	odds := []*oddsapi.Odds{event(
+		bookMaker("book1", updated, market("spreads",
+			&oddsapi.Outcome{Name: "Home", Price: 1.91, Point: point(-2.5)},
+			&oddsapi.Outcome{Name: "Away", Price: 1.91, Point: point(2.5)})),
+		bookMaker("book2", updated, market("spreads",
+			&oddsapi.Outcome{Name: "Home", Price: 1.91, Point: point(-4)},
+			&oddsapi.Outcome{Name: "Away", Price: 1.91, Point: point(4)})),
+	)}
+
+	result, err := FindMiddles(odds, Options{Bankroll: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 middle, got %d", len(result))
+	}
+
+	m := result[0]
+	if m.Legs[0].BookMaker != "book1" || m.Legs[0].Outcome != "Home" {
+		t.Errorf("expected home -2.5 at book1, got %s %s", m.Legs[0].Outcome, m.Legs[0].BookMaker)
+	}
+	if m.Legs[1].BookMaker != "book2" || m.Legs[1].Outcome != "Away" {
+		t.Errorf("expected away +4 at book2, got %s %s", m.Legs[1].Outcome, m.Legs[1].BookMaker)
+	}
+	if m.Low != 2.5 || m.High != 4 || m.Width != 1.5 {
+		t.Errorf("expected window 2.5-4, got %v-%v width %v", m.Low, m.High, m.Width)
+	}
+	if len(m.Pushes) != 1 || m.Pushes[0] != 4 {
+		t.Errorf("expected a push at 4, got %v", m.Pushes)
+	}
+
+	expectedHold := 2/1.91 - 1
+	if math.Abs(m.Hold-expectedHold) > 1e-9 {
+		t.Errorf("expected hold %v, got %v", expectedHold, m.Hold)
+	}
+	if m.BreakEvenHitRate != m.Hold {
+		t.Errorf("expected break-even hit rate to equal the hold")
+	}
+}
+
+func TestFindMiddles_Totals(t *testing.T) {
+	odds := []*oddsapi.Odds{event(
+		bookMaker("book1", updated, market("totals",
+			&oddsapi.Outcome{Name: "Over", Price: 1.95, Point: point(220.5)},
+			&oddsapi.Outcome{Name: "Under", Price: 1.87, Point: point(220.5)})),
+		bookMaker("book2", updated, market("totals",
+			&oddsapi.Outcome{Name: "Over", Price: 1.8, Point: point(223)},
+			&oddsapi.Outcome{Name: "Under", Price: 2.1, Point: point(223)})),
+	)}
+
+	result, err := FindMiddles(odds, Options{Bankroll: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 middle, got %d", len(result))
+	}
+
+	m := result[0]
+	if m.Low != 220.5 || m.High != 223 {
+		t.Errorf("expected window 220.5-223, got %v-%v", m.Low, m.High)
+	}
+	if m.Legs[0].Outcome != "Over" || m.Legs[1].Outcome != "Under" {
+		t.Errorf("expected over then under legs")
+	}
+	if m.Hold >= 0 || m.BreakEvenHitRate != 0 {
+		t.Errorf("expected a negative hold with zero break-even rate, got %v and %v", m.Hold, m.BreakEvenHitRate)
+	}
+}
+
+func TestFindMiddles_IgnoresSameLine(t *testing.T) {
+	odds := []*oddsapi.Odds{event(
+		bookMaker("book1", updated, market("spreads",
+			&oddsapi.Outcome{Name: "Home", Price: 1.91, Point: point(-3)},
+			&oddsapi.Outcome{Name: "Away", Price: 1.91, Point: point(3)})),
+		bookMaker("book2", updated, market("spreads",
+			&oddsapi.Outcome{Name: "Home", Price: 1.95, Point: point(-3)},
+			&oddsapi.Outcome{Name: "Away", Price: 1.87, Point: point(3)})),
+	)}
+
+	result, err := FindMiddles(odds, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 0 {
+		t.Errorf("expected no middles, got %d", len(result))
+	}
+}
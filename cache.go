@@ -0,0 +1,220 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Endpoint string
+
+const (
+	EndpointSports    Endpoint = "sports"
+	EndpointEvents    Endpoint = "events"
+	EndpointOdds      Endpoint = "odds"
+	EndpointEventOdds Endpoint = "event_odds"
+	EndpointScores    Endpoint = "scores"
+)
+
+// CacheTTLs is how long responses of each endpoint are reused. Endpoints
+// without a TTL are always fetched.
+type CacheTTLs map[Endpoint]time.Duration
+
+// DefaultCacheTTLs only caches the endpoints that rarely change and do not
+// count against the quota the same way odds do.
+var DefaultCacheTTLs = CacheTTLs{
+	EndpointSports: time.Hour,
+	EndpointEvents: 5 * time.Minute,
+}
+
+// listParams are the comma-separated query values whose order does not
+// change the response
+var listParams = []string{"regions", "markets", "bookmakers", "eventIds"}
+
+// endpointOf maps a request path to its endpoint
+func endpointOf(u *url.URL) (Endpoint, bool) {
+	segments, err := apiPath(u)
+	if err != nil {
+		return "", false
+	}
+	switch {
+	case len(segments) == 0:
+		return EndpointSports, true
+	case len(segments) == 2 && segments[1] == "odds":
+		return EndpointOdds, true
+	case len(segments) == 2 && segments[1] == "events":
+		return EndpointEvents, true
+	case len(segments) == 4 && segments[1] == "events" && segments[3] == "odds":
+		return EndpointEventOdds, true
+	case len(segments) == 2 && segments[1] == "scores":
+		return EndpointScores, true
+	}
+	return "", false
+}
+
+// CanonicalKey identifies a request regardless of api key, query order and
+// the order of list values, so equivalent params share a cache entry. The
+// host is part of the key, so clients of different upstreams never do.
+func CanonicalKey(u *url.URL) string {
+	q := u.Query()
+	q.Del("apiKey")
+	for _, key := range listParams {
+		if v := q.Get(key); v != "" {
+			values := queryList(q, key)
+			sort.Strings(values)
+			q.Set(key, strings.Join(values, ","))
+		}
+	}
+	return u.Host + strings.TrimSuffix(u.Path, "/") + "?" + q.Encode()
+}
+
+// ErrCacheMiss is returned by a Cache that has no entry for a key
+var ErrCacheMiss = errors.New("cache miss")
+
+// CachedResponse is what a Cache stores for a response: the raw body, the
+// quota headers and when it was fetched.
+type CachedResponse struct {
+	Body      []byte      `json:"body"`
+	Header    http.Header `json:"header"`
+	FetchedAt time.Time   `json:"fetched_at"`
+}
+
+// Cache stores responses for the client. Implementations must be safe for
+// concurrent use. See the cache package for disk and Redis backends that
+// share entries between processes.
+type Cache interface {
+	// Get returns ErrCacheMiss when the key is missing or expired
+	Get(ctx context.Context, key string) (*CachedResponse, error)
+	Set(ctx context.Context, key string, r *CachedResponse, ttl time.Duration) error
+}
+
+type memoryEntry struct {
+	response *CachedResponse
+	expires  time.Time
+}
+
+// MemoryCache is the Cache used when no other backend is set
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]*memoryEntry)}
+}
+
+func (m *MemoryCache) Get(_ context.Context, key string) (*CachedResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	if !time.Now().Before(e.expires) {
+		delete(m.entries, key)
+		return nil, ErrCacheMiss
+	}
+	return e.response, nil
+}
+
+func (m *MemoryCache) Set(_ context.Context, key string, r *CachedResponse, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for k, e := range m.entries {
+		if !now.Before(e.expires) {
+			delete(m.entries, k)
+		}
+	}
+	m.entries[key] = &memoryEntry{response: r, expires: now.Add(ttl)}
+	return nil
+}
+
+// quotaHeaders keeps only the headers a cached response needs to report quota
+func quotaHeaders(h http.Header) http.Header {
+	q := make(http.Header)
+	for _, k := range []string{headerRequestsRemaining, headerRequestsUsed, headerRequestsLast} {
+		if v := h.Get(k); v != "" {
+			q.Set(k, v)
+		}
+	}
+	return q
+}
+
+type responseCache struct {
+	ttls    CacheTTLs
+	backend Cache
+	now     func() time.Time
+}
+
+func newResponseCache(ttls CacheTTLs, backend Cache) *responseCache {
+	if backend == nil {
+		backend = NewMemoryCache()
+	}
+	return &responseCache{
+		ttls:    ttls,
+		backend: backend,
+		now:     time.Now,
+	}
+}
+
+// key returns the cache key and ttl of a request, or false when its
+// endpoint is not cached
+func (rc *responseCache) key(u *url.URL) (string, time.Duration, bool) {
+	endpoint, ok := endpointOf(u)
+	if !ok || rc.ttls[endpoint] <= 0 {
+		return "", 0, false
+	}
+	return CanonicalKey(u), rc.ttls[endpoint], true
+}
+
+// get treats backend errors as misses, so an unavailable cache only costs
+// an upstream request. Entries older than the client's ttl are ignored even
+// when another process stored them with a longer one.
+func (rc *responseCache) get(ctx context.Context, key string, ttl time.Duration) (*CachedResponse, bool) {
+	r, err := rc.backend.Get(ctx, key)
+	if err != nil || r == nil {
+		return nil, false
+	}
+	if rc.now().Sub(r.FetchedAt) >= ttl {
+		return nil, false
+	}
+	return r, true
+}
+
+func (rc *responseCache) set(ctx context.Context, key string, r *CachedResponse, ttl time.Duration) {
+	_ = rc.backend.Set(ctx, key, r, ttl)
+}
+
+// cachedResponse rebuilds the response of a cache hit. It reports the quota
+// from when the entry was fetched, with a cost of zero for this request.
+func cachedResponse(r *CachedResponse, req *http.Request) *Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	if header.Get(headerRequestsLast) != "" {
+		header.Set(headerRequestsLast, "0")
+	}
+	return &Response{
+		Response: &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     header,
+			Body:       http.NoBody,
+			Request:    req,
+		},
+		Cached: true,
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"io/fs"
+	"net/http"
+	"oddsapi"
+	"oddsapi/internal/testserver"
+	"os"
+	"testing"
+	"time"
+)
+
+func testCache(t *testing.T, c oddsapi.Cache, expire func(time.Duration)) {
+	ctx := context.Background()
+	if _, err := c.Get(ctx, "missing"); !errors.Is(err, oddsapi.ErrCacheMiss) {
+		t.Errorf("expected a cache miss, got %v", err)
+	}
+
+	fetchedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	header := http.Header{"X-Requests-Remaining": {"100"}}
+	err := c.Set(ctx, "key", &oddsapi.CachedResponse{Body: []byte(`[]`), Header: header, FetchedAt: fetchedAt}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := c.Get(ctx, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(r.Body) != "[]" || r.Header.Get("X-Requests-Remaining") != "100" || !r.FetchedAt.Equal(fetchedAt) {
+		t.Errorf("unexpected cached response %+v", r)
+	}
+
+	expire(2 * time.Minute)
+	if _, err = c.Get(ctx, "key"); !errors.Is(err, oddsapi.ErrCacheMiss) {
+		t.Errorf("expected an expired entry to miss, got %v", err)
+	}
+}
+
+func TestDisk(t *testing.T) {
+	dir := t.TempDir()
+	d, err := NewDisk(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCache(t, d, func(time.Duration) {
+		// Rewrite the entry as already expired
+		_ = d.Set(context.Background(), "key", &oddsapi.CachedResponse{}, -time.Second)
+	})
+	if err = d.Prune(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(d.path("key")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected the expired entry to be pruned, got %v", err)
+	}
+}
+
+func TestRedis(t *testing.T) {
+	m := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	defer func() { _ = client.Close() }()
+
+	r := NewRedis(client, "")
+	testCache(t, r, m.FastForward)
+}
+
+// TestRedis_SharedBetweenClients checks that a second client, as in another
+// process, is served from the entry the first one stored
+func TestRedis_SharedBetweenClients(t *testing.T) {
+	server := testserver.New(t)
+
+	m := miniredis.RunT(t)
+	newClient := func() *oddsapi.Client {
+		rc := redis.NewClient(&redis.Options{Addr: m.Addr()})
+		t.Cleanup(func() { _ = rc.Close() })
+		c, err := oddsapi.NewClient("key", 100, oddsapi.SetBaseUrl(server.URL), oddsapi.SetCacheBackend(NewRedis(rc, "")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	if _, _, err := newClient().SportsService.GetSports(); err != nil {
+		t.Fatal(err)
+	}
+	sports, resp, err := newClient().SportsService.GetSports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Cached || server.Hits.Load() != 1 || len(sports) != 1 {
+		t.Errorf("expected the second client to hit the shared cache, got %d requests", server.Hits.Load())
+	}
+	if q := resp.Quota(); q.Remaining != 100 || q.Last != 0 {
+		t.Errorf("expected the cached quota headers, got %+v", q)
+	}
+}
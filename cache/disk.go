@@ -0,0 +1,106 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cache provides oddsapi.Cache backends that processes on a host, or
+// across hosts, can share.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"oddsapi"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type diskEntry struct {
+	Expires  time.Time               `json:"expires"`
+	Response *oddsapi.CachedResponse `json:"response"`
+}
+
+// Disk stores each response as a JSON file named by the hash of its key.
+// Files are replaced atomically, so readers never see a partial write.
+type Disk struct {
+	dir string
+}
+
+func NewDisk(dir string) (*Disk, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Disk{dir: dir}, nil
+}
+
+func (d *Disk) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (d *Disk) Get(_ context.Context, key string) (*oddsapi.CachedResponse, error) {
+	path := d.path(key)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, oddsapi.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var e diskEntry
+	if err = json.Unmarshal(data, &e); err != nil || e.Response == nil {
+		_ = os.Remove(path)
+		return nil, oddsapi.ErrCacheMiss
+	}
+	if !time.Now().Before(e.Expires) {
+		_ = os.Remove(path)
+		return nil, oddsapi.ErrCacheMiss
+	}
+	return e.Response, nil
+}
+
+func (d *Disk) Set(_ context.Context, key string, r *oddsapi.CachedResponse, ttl time.Duration) error {
+	data, err := json.Marshal(&diskEntry{Expires: time.Now().Add(ttl), Response: r})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err = f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), d.path(key))
+}
+
+// Prune removes expired entries, which are otherwise only removed when read
+func (d *Disk) Prune() error {
+	files, err := filepath.Glob(filepath.Join(d.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var e diskEntry
+		if json.Unmarshal(data, &e) != nil || !now.Before(e.Expires) {
+			_ = os.Remove(path)
+		}
+	}
+	return nil
+}
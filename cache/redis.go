@@ -0,0 +1,52 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"oddsapi"
+	"time"
+)
+
+const DefaultRedisPrefix = "oddsapi:"
+
+// Redis stores responses as JSON values that expire with their ttl
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedis uses client with keys under prefix, or DefaultRedisPrefix when blank
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	if prefix == "" {
+		prefix = DefaultRedisPrefix
+	}
+	return &Redis{client: client, prefix: prefix}
+}
+
+func (r *Redis) Get(ctx context.Context, key string) (*oddsapi.CachedResponse, error) {
+	data, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, oddsapi.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+	var resp oddsapi.CachedResponse
+	if err = json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (r *Redis) Set(ctx context.Context, key string, resp *oddsapi.CachedResponse, ttl time.Duration) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, r.prefix+key, data, ttl).Err()
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"net/url"
+	"oddsapi/internal/testserver"
+	"testing"
+	"time"
+)
+
+func TestClient_Cache(t *testing.T) {
+	server := testserver.New(t)
+	c, err := NewClient("key", 100, SetBaseUrl(server.URL), SetCache(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sports, resp, err := c.SportsService.GetSports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Cached {
+		t.Error("expected the first response to be fetched")
+	}
+	sports[0].Key = "mutated"
+
+	sports, resp, err = c.SportsService.GetSports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Cached || server.Hits.Load() != 1 {
+		t.Errorf("expected a cache hit, got %d requests", server.Hits.Load())
+	}
+	if sports[0].Key != "basketball_nba" {
+		t.Errorf("expected a fresh copy of the cached data, got %s", sports[0].Key)
+	}
+	if q := resp.Quota(); !q.Known || q.Remaining != 100 || q.Last != 0 {
+		t.Errorf("expected the cached quota with no cost, got %+v", q)
+	}
+
+	c.cache.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, resp, _ = c.SportsService.GetSports(); resp.Cached || server.Hits.Load() != 2 {
+		t.Error("expected an expired entry to be refetched")
+	}
+
+	params := c.OddsService.NewOddsParams("basketball_nba")
+	_ = params.SetRegions(RegionUs)
+	_, _, _ = c.OddsService.GetOdds(params)
+	_, _, _ = c.OddsService.GetOdds(params)
+	if server.Hits.Load() != 4 {
+		t.Errorf("expected odds not to be cached by default, got %d requests", server.Hits.Load())
+	}
+}
+
+func TestClient_CacheCanonicalParams(t *testing.T) {
+	server := testserver.New(t)
+	c, err := NewClient("key", 100, SetBaseUrl(server.URL), SetCache(CacheTTLs{EndpointOdds: time.Minute}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := NewOddsParams("key", "basketball_nba")
+	_ = first.SetRegions(RegionUs, RegionUk)
+	first.SetBookmakers("pinnacle", "draftkings")
+	second := NewOddsParams("other-key", "basketball_nba")
+	_ = second.SetRegions(RegionUk, RegionUs)
+	second.SetBookmakers("draftkings", "pinnacle")
+
+	for _, p := range []*OddsParams{first, second} {
+		if _, _, err = c.OddsService.GetOdds(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if server.Hits.Load() != 1 {
+		t.Errorf("expected equivalent params to share an entry, got %d requests", server.Hits.Load())
+	}
+
+	second.SetBookmakers("pinnacle")
+	if _, _, err = c.OddsService.GetOdds(second); err != nil {
+		t.Fatal(err)
+	}
+	if server.Hits.Load() != 2 {
+		t.Errorf("expected different params to be fetched, got %d requests", server.Hits.Load())
+	}
+}
+
+func TestClient_CacheSharedBackend(t *testing.T) {
+	backend := NewMemoryCache()
+	servers := []*testserver.Server{testserver.New(t), testserver.New(t)}
+	for _, server := range servers {
+		c, err := NewClient("key", 100, SetBaseUrl(server.URL), SetCacheBackend(backend))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, resp, err := c.SportsService.GetSports()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Cached {
+			t.Errorf("expected %s to be fetched rather than served another upstream's entry", server.URL)
+		}
+	}
+	for i, server := range servers {
+		if n := server.Hits.Load(); n != 1 {
+			t.Errorf("expected 1 request to upstream %d, got %d", i, n)
+		}
+	}
+}
+
+func TestEndpointOf(t *testing.T) {
+	cases := map[string]Endpoint{
+		"/v4/sports":                        EndpointSports,
+		"/v4/sports/nba/odds/":              EndpointOdds,
+		"/v4/sports/nba/events":             EndpointEvents,
+		"/v4/sports/nba/events/event1/odds": EndpointEventOdds,
+		"/v4/sports/nba/scores":             EndpointScores,
+	}
+	for path, expected := range cases {
+		endpoint, ok := endpointOf(&url.URL{Path: path})
+		if !ok || endpoint != expected {
+			t.Errorf("expected %s for %s, got %s", expected, path, endpoint)
+		}
+	}
+	if _, ok := endpointOf(&url.URL{Path: "/v4/sports/nba/participants"}); ok {
+		t.Error("expected no endpoint for an unknown path")
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cassette records the client's HTTP traffic as JSON lines and
+// replays it, so code built on the client can be tested offline.
+//
+// Record once against the live API:
+//
+//	f, _ := os.Create("testdata/odds.jsonl")
+//	client, _ := oddsapi.NewClient(key, 10, oddsapi.SetTransport(cassette.NewRecorder(f, nil)))
+//
+// and replay in tests:
+//
+//	replayer, _ := cassette.Load("testdata/odds.jsonl")
+//	client, _ := oddsapi.NewClient("test", 10, oddsapi.SetTransport(replayer))
+package cassette
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Redacted replaces the api key of recorded requests
+const Redacted = "REDACTED"
+
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Status     string      `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Interaction is one line of a cassette
+type Interaction struct {
+	Request    *Request  `json:"request"`
+	Response   *Response `json:"response"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+func redact(u *url.URL) string {
+	r := *u
+	q := r.Query()
+	if q.Has("apiKey") {
+		q.Set("apiKey", Redacted)
+		r.RawQuery = q.Encode()
+	}
+	return r.String()
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package cassette
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"oddsapi"
+	"strings"
+	"testing"
+)
+
+func TestReplayer(t *testing.T) {
+	replayer, err := Load("testdata/odds.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := oddsapi.NewClient("test", 10, oddsapi.SetTransport(replayer))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sports, _, err := client.SportsService.GetSports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sports) != 1 || sports[0].Key != "basketball_nba" {
+		t.Errorf("unexpected sports %v", sports)
+	}
+
+	params := client.OddsService.NewOddsParams("basketball_nba")
+	_ = params.SetRegions(oddsapi.RegionUk, oddsapi.RegionUs)
+	_ = params.SetMarkets(oddsapi.MarketH2H)
+	odds, resp, err := client.OddsService.GetOdds(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(odds) != 1 || odds[0].BookMakers[0].Markets[0].Outcomes[1].Price != 1.95 {
+		t.Errorf("unexpected odds %v", odds)
+	}
+	if q := resp.Quota(); q.Remaining != 478 || q.Last != 2 {
+		t.Errorf("expected the recorded quota, got %+v", q)
+	}
+
+	_ = params.SetMarkets(oddsapi.MarketSpreads)
+	if _, _, err = client.OddsService.GetOdds(params); err == nil {
+		t.Error("expected an error for a request that was not recorded")
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	var status = http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Requests-Remaining", "100")
+		w.WriteHeader(status)
+		status = http.StatusOK
+		_, _ = w.Write([]byte(`[{"key":"basketball_nba","active":true}]`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	recorder := NewRecorder(&buf, nil)
+	client, err := oddsapi.NewClient("secret-key", 10, oddsapi.SetBaseUrl(server.URL), oddsapi.SetTransport(recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = client.SportsService.GetSports(); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "secret-key") {
+		t.Error("expected the api key to be redacted")
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Fatalf("expected the retried request to be recorded twice, got %d lines", lines)
+	}
+
+	replayer, err := NewReplayer(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err = oddsapi.NewClient("other-key", 10, oddsapi.SetBaseUrl(server.URL), oddsapi.SetTransport(replayer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	// The replayed 500 is retried like the original and the recorded 200 served next
+	sports, resp, err := client.SportsService.GetSports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || len(sports) != 1 || sports[0].Key != "basketball_nba" {
+		t.Errorf("unexpected replay %d %v", resp.StatusCode, sports)
+	}
+}
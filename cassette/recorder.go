@@ -0,0 +1,62 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Recorder is a RoundTripper that writes every completed exchange to a
+// cassette. Requests that fail in transport are not recorded.
+type Recorder struct {
+	next http.RoundTripper
+
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewRecorder records to w the exchanges made through next, or through
+// http.DefaultTransport when next is nil
+func NewRecorder(w io.Writer, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{next: next, enc: json.NewEncoder(w)}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	i := &Interaction{
+		Request: &Request{Method: req.Method, URL: redact(req.URL)},
+		Response: &Response{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Header:     resp.Header.Clone(),
+			Body:       string(body),
+		},
+		RecordedAt: time.Now().UTC(),
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err = r.enc.Encode(i); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
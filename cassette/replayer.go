@@ -0,0 +1,104 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package cassette
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"oddsapi"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Replayer is a RoundTripper that serves recorded responses. Requests match
+// on method and oddsapi.CanonicalKey without the host, so the base url, the
+// api key and the order of query values do not matter. Repeated requests get the recorded responses in
+// order, and the last one again once they run out.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions map[string][]*Interaction
+	served       map[string]int
+}
+
+func Load(path string) (*Replayer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return NewReplayer(f)
+}
+
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	rp := &Replayer{
+		interactions: make(map[string][]*Interaction),
+		served:       make(map[string]int),
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var i Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &i); err != nil {
+			return nil, fmt.Errorf("error parsing cassette line %d: %w", line, err)
+		}
+		if i.Request == nil || i.Response == nil {
+			return nil, fmt.Errorf("incomplete interaction on cassette line %d", line)
+		}
+		u, err := url.Parse(i.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid url on cassette line %d: %w", line, err)
+		}
+		key := matchKey(i.Request.Method, u)
+		rp.interactions[key] = append(rp.interactions[key], &i)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rp, nil
+}
+
+func matchKey(method string, u *url.URL) string {
+	local := *u
+	local.Host = ""
+	return method + " " + oddsapi.CanonicalKey(&local)
+}
+
+func (rp *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := matchKey(req.Method, req.URL)
+
+	rp.mu.Lock()
+	recorded := rp.interactions[key]
+	if len(recorded) == 0 {
+		rp.mu.Unlock()
+		return nil, fmt.Errorf("no recorded response for %s %s", req.Method, redact(req.URL))
+	}
+	n := rp.served[key]
+	rp.served[key]++
+	rp.mu.Unlock()
+
+	r := recorded[min(n, len(recorded)-1)].Response
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        r.Status,
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,242 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package closing captures the final odds of each event shortly before it
+// commences and keeps them as the closing line.
+package closing
+
+import (
+	"context"
+	"fmt"
+	"oddsapi"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultLead         = 2 * time.Minute
+	DefaultTick         = 15 * time.Second
+	DefaultEventRefresh = 30 * time.Minute
+)
+
+// Result reports a captured line or the error that prevented it.
+type Result struct {
+	Line *Line
+	Err  error
+}
+
+type pending struct {
+	sportKey string
+	event    *oddsapi.Event
+	commence time.Time
+}
+
+type Capturer struct {
+	// Lead is how long before commence time the snapshot is taken
+	Lead time.Duration
+
+	// Tick is how often pending events are checked
+	Tick time.Duration
+
+	// EventRefresh is how often events are listed for each sport
+	EventRefresh time.Duration
+
+	store  Store
+	now    func() time.Time
+	events func(sportKey string) ([]*oddsapi.Event, error)
+	odds   func(params *oddsapi.EventOddsParams) (*oddsapi.Odds, error)
+
+	mu       sync.Mutex
+	sports   map[string]*oddsapi.EventOddsParams
+	pending  map[string]*pending
+	captured map[string]time.Time
+}
+
+func NewCapturer(client *oddsapi.Client, store Store) *Capturer {
+	c := &Capturer{
+		Lead:         DefaultLead,
+		Tick:         DefaultTick,
+		EventRefresh: DefaultEventRefresh,
+		store:        store,
+		now:          time.Now,
+		sports:       make(map[string]*oddsapi.EventOddsParams),
+		pending:      make(map[string]*pending),
+		captured:     make(map[string]time.Time),
+	}
+	c.events = func(sportKey string) ([]*oddsapi.Event, error) {
+		data, _, err := client.EventService.GetEvents(client.EventService.NewEventParams(sportKey))
+		return data, err
+	}
+	c.odds = func(params *oddsapi.EventOddsParams) (*oddsapi.Odds, error) {
+		data, _, err := client.EventOddsService.GetOdds(params)
+		return data, err
+	}
+	return c
+}
+
+// AddSport captures closing lines for every event in the sport. Params is a
+// template for the regions, markets and bookmakers to capture; its sport
+// and event keys are set per event.
+func (c *Capturer) AddSport(sportKey string, params *oddsapi.EventOddsParams) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sports[sportKey] = params
+}
+
+// Track schedules events for capture. Events already captured are ignored.
+func (c *Capturer) Track(sportKey string, events ...*oddsapi.Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.sports[sportKey]; !ok {
+		return fmt.Errorf("sport %s has not been added", sportKey)
+	}
+	for _, e := range events {
+		if _, ok := c.captured[e.Id]; ok {
+			continue
+		}
+		commence, err := time.Parse(time.RFC3339, e.CommenceTime)
+		if err != nil {
+			return fmt.Errorf("invalid commence time for event %s: %w", e.Id, err)
+		}
+		c.pending[e.Id] = &pending{sportKey: sportKey, event: e, commence: commence}
+	}
+	return nil
+}
+
+// due removes and returns events inside the capture window. Events that
+// commenced before they could be captured are dropped, and captured events
+// are forgotten once they commence.
+func (c *Capturer) due(now time.Time) []*pending {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, commence := range c.captured {
+		if !now.Before(commence) {
+			delete(c.captured, id)
+		}
+	}
+	var result []*pending
+	for id, p := range c.pending {
+		if !now.Before(p.commence) {
+			delete(c.pending, id)
+			continue
+		}
+		if !now.Before(p.commence.Add(-c.Lead)) {
+			delete(c.pending, id)
+			result = append(result, p)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].commence.Before(result[j].commence) })
+	return result
+}
+
+func (c *Capturer) capture(p *pending) (*Line, error) {
+	c.mu.Lock()
+	params := *c.sports[p.sportKey]
+	c.mu.Unlock()
+	params.SportKey = p.sportKey
+	params.EventKey = p.event.Id
+
+	data, err := c.odds(&params)
+	if err != nil {
+		c.retry(p)
+		return nil, fmt.Errorf("error capturing closing line for event %s: %w", p.event.Id, err)
+	}
+	line := &Line{
+		EventId:      p.event.Id,
+		SportKey:     p.sportKey,
+		CommenceTime: p.commence,
+		CapturedAt:   c.now(),
+		Odds:         data,
+	}
+	if err = c.store.SaveClosingLine(line); err != nil {
+		c.retry(p)
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.captured[p.event.Id] = p.commence
+	c.mu.Unlock()
+	return line, nil
+}
+
+// retry puts a failed event back so the next tick tries again until it
+// commences.
+func (c *Capturer) retry(p *pending) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.pending[p.event.Id]; !ok {
+		c.pending[p.event.Id] = p
+	}
+}
+
+func (c *Capturer) refreshEvents() []error {
+	c.mu.Lock()
+	keys := make([]string, 0, len(c.sports))
+	for key := range c.sports {
+		keys = append(keys, key)
+	}
+	c.mu.Unlock()
+
+	var errs []error
+	for _, key := range keys {
+		events, err := c.events(key)
+		if err == nil {
+			err = c.Track(key, events...)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Start captures closing lines until ctx is cancelled, then closes the
+// returned channel.
+func (c *Capturer) Start(ctx context.Context) <-chan *Result {
+	results := make(chan *Result)
+	go c.run(ctx, results)
+	return results
+}
+
+func (c *Capturer) run(ctx context.Context, results chan<- *Result) {
+	defer close(results)
+
+	tick := time.NewTicker(c.Tick)
+	defer tick.Stop()
+
+	var refreshed time.Time
+	for {
+		now := c.now()
+		if now.Sub(refreshed) >= c.EventRefresh {
+			for _, err := range c.refreshEvents() {
+				if !send(ctx, results, &Result{Err: err}) {
+					return
+				}
+			}
+			refreshed = now
+		}
+
+		for _, p := range c.due(now) {
+			line, err := c.capture(p)
+			if !send(ctx, results, &Result{Line: line, Err: err}) {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+func send(ctx context.Context, results chan<- *Result, r *Result) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case results <- r:
+		return true
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package closing
+
+import (
+	"context"
+	"errors"
+	"oddsapi"
+	"testing"
+	"time"
+)
+
+func TestCapturer_Start(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewCapturer(nil, store)
+	c.Tick = time.Millisecond
+	c.now = func() time.Time { return now }
+	c.events = func(sportKey string) ([]*oddsapi.Event, error) {
+		return []*oddsapi.Event{
+			{Id: "soon", CommenceTime: now.Add(time.Minute).Format(time.RFC3339)},
+			{Id: "later", CommenceTime: now.Add(time.Hour).Format(time.RFC3339)},
+			{Id: "started", CommenceTime: now.Add(-time.Minute).Format(time.RFC3339)},
+		}, nil
+	}
+	var requested []*oddsapi.EventOddsParams
+	c.odds = func(params *oddsapi.EventOddsParams) (*oddsapi.Odds, error) {
+		requested = append(requested, params)
+		return &oddsapi.Odds{Id: params.EventKey, SportKey: params.SportKey}, nil
+	}
+	c.AddSport("basketball_nba", &oddsapi.EventOddsParams{Region: "us", Markets: "h2h"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	result := <-c.Start(ctx)
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	if result.Line.EventId != "soon" || !result.Line.CapturedAt.Equal(now) {
+		t.Errorf("expected the closing line for soon captured at %s, got %+v", now, result.Line)
+	}
+	if len(requested) != 1 || requested[0].Region != "us" || requested[0].SportKey != "basketball_nba" {
+		t.Errorf("expected one request using the sport params, got %+v", requested)
+	}
+
+	saved, err := store.ClosingLine("soon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Odds.Id != "soon" || !saved.CommenceTime.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected the stored line to round trip, got %+v", saved)
+	}
+
+	if _, err = store.ClosingLine("later"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected no closing line for later, got %v", err)
+	}
+}
+
+func TestCapturer_TrackSkipsCaptured(t *testing.T) {
+	c := NewCapturer(nil, nil)
+	c.AddSport("soccer_epl", &oddsapi.EventOddsParams{})
+	c.captured["done"] = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	err := c.Track("soccer_epl", &oddsapi.Event{Id: "done", CommenceTime: "2024-01-01T00:00:00Z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.pending) != 0 {
+		t.Error("expected captured events to be ignored")
+	}
+
+	if err = c.Track("unknown"); err == nil {
+		t.Error("expected an error for an unregistered sport")
+	}
+}
+
+func TestCapturer_RetriesFailedCapture(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewCapturer(nil, store)
+	c.now = func() time.Time { return now }
+	var calls int
+	c.odds = func(params *oddsapi.EventOddsParams) (*oddsapi.Odds, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("upstream error")
+		}
+		return &oddsapi.Odds{Id: params.EventKey}, nil
+	}
+	c.AddSport("basketball_nba", &oddsapi.EventOddsParams{})
+	event := &oddsapi.Event{Id: "soon", CommenceTime: now.Add(time.Minute).Format(time.RFC3339)}
+	if err = c.Track("basketball_nba", event); err != nil {
+		t.Fatal(err)
+	}
+
+	due := c.due(now)
+	if len(due) != 1 {
+		t.Fatalf("expected 1 due event, got %d", len(due))
+	}
+	if _, err = c.capture(due[0]); err == nil {
+		t.Fatal("expected the first capture to fail")
+	}
+
+	due = c.due(now)
+	if len(due) != 1 {
+		t.Fatalf("expected the failed event to be due again, got %d", len(due))
+	}
+	if _, err = c.capture(due[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.captured["soon"]; !ok {
+		t.Error("expected the event to be captured")
+	}
+
+	c.due(now.Add(time.Minute))
+	if len(c.captured) != 0 {
+		t.Error("expected captured events to be pruned once they commence")
+	}
+}
+
+func TestFileStore_InvalidEventId(t *testing.T) {
+	store, _ := NewFileStore(t.TempDir())
+	if err := store.SaveClosingLine(&Line{EventId: "../escape"}); err == nil {
+		t.Error("expected an error for an event id containing a path")
+	}
+}
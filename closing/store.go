@@ -0,0 +1,86 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package closing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"oddsapi"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNotFound is returned when no closing line has been captured for an event
+var ErrNotFound = errors.New("closing line not found")
+
+// Line is the last odds snapshot taken for an event before it commenced.
+type Line struct {
+	EventId      string        `json:"event_id"`
+	SportKey     string        `json:"sport_key"`
+	CommenceTime time.Time     `json:"commence_time"`
+	CapturedAt   time.Time     `json:"captured_at"`
+	Odds         *oddsapi.Odds `json:"odds"`
+}
+
+type Store interface {
+	SaveClosingLine(line *Line) error
+	ClosingLine(eventId string) (*Line, error)
+}
+
+// FileStore keeps one JSON file per event under a directory.
+type FileStore struct {
+	dir string
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (f *FileStore) path(eventId string) (string, error) {
+	if eventId == "" || eventId != filepath.Base(eventId) {
+		return "", fmt.Errorf("invalid event id: %q", eventId)
+	}
+	return filepath.Join(f.dir, eventId+".json"), nil
+}
+
+func (f *FileStore) SaveClosingLine(line *Line) error {
+	p, err := f.path(line.EventId)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial line
+	tmp := p + ".tmp"
+	if err = os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+func (f *FileStore) ClosingLine(eventId string) (*Line, error) {
+	p, err := f.path(eventId)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var line Line
+	if err = json.Unmarshal(b, &line); err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
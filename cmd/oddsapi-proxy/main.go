@@ -0,0 +1,102 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Command oddsapi-proxy serves the Odds API's /v4 paths to internal services
+// with a shared api key, caching responses and coalescing identical requests.
+//
+//	oddsapi-proxy -addr :8080 -ttl 30s -profile live
+//
+// The api key and upstream come from the same config profiles as the oddsapi
+// command. Consumers point their client's base url at the proxy, and with
+// -consumers use their own token as the api key:
+//
+//	consumers:
+//	  - name: pricing
+//	    token: ...
+//	    limit: 5000
+//	    period: 720h
+//	  - name: ops
+//	    token: ...
+//	    admin: true
+//
+// GET /usage reports what each consumer has spent.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"oddsapi"
+	"oddsapi/proxy"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	ttl := flag.Duration("ttl", proxy.DefaultTTL, "how long responses are cached")
+	configPath := flag.String("config", "", "config file, defaults to $ODDSAPI_CONFIG")
+	profileName := flag.String("profile", "", "config profile")
+	consumersPath := flag.String("consumers", "", "YAML or TOML file of consumer tokens and limits, open to anyone when unset")
+	flag.Parse()
+
+	var config *oddsapi.Config
+	if *configPath == "" {
+		*configPath = os.Getenv(oddsapi.EnvConfig)
+	}
+	if *configPath != "" {
+		var err error
+		if config, err = oddsapi.LoadConfig(*configPath); err != nil {
+			log.Fatalf("error loading config: %s", err)
+		}
+	}
+	profile, err := config.Profile(*profileName)
+	if err != nil {
+		log.Fatalf("error loading profile: %s", err)
+	}
+	if profile.ApiKey == "" {
+		log.Fatalf("no api key provided, set a profile api_key or %s", oddsapi.EnvApiKey)
+	}
+
+	client, err := profile.NewClient()
+	if err != nil {
+		log.Fatalf("error creating client: %s", err)
+	}
+
+	handler := proxy.New(client, *ttl)
+	if *consumersPath != "" {
+		consumers, err := proxy.LoadConsumers(*consumersPath)
+		if err != nil {
+			log.Fatalf("error loading consumers: %s", err)
+		}
+		for _, c := range consumers {
+			if err = handler.AddConsumer(c); err != nil {
+				log.Fatalf("error adding consumer: %s", err)
+			}
+		}
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdown)
+	}()
+
+	log.Printf("proxying %s on %s", client.GetBaseUrl(), *addr)
+	if err = server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("error serving: %s", err)
+	}
+}
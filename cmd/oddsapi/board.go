@@ -0,0 +1,264 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"oddsapi"
+	"oddsapi/poller"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiDim    = "\x1b[2m"
+	ansiHome   = "\x1b[H"
+	ansiClear  = "\x1b[2J"
+	ansiHidden = "\x1b[?25l"
+	ansiShown  = "\x1b[?25h"
+)
+
+type moveKey struct {
+	eventId   string
+	bookMaker string
+	outcome   string
+}
+
+type move struct {
+	lengthened bool
+	at         time.Time
+}
+
+// board is a grid of the outcomes of one market against bookmakers. The best
+// price of each row is bold and prices that moved within highlight are green
+// when they lengthened and red when they shortened.
+type board struct {
+	market    string
+	format    oddsapi.OddsFormat
+	highlight time.Duration
+	color     bool
+
+	odds      []*oddsapi.Odds
+	fetchedAt time.Time
+	moves     map[moveKey]*move
+	quota     oddsapi.Quota
+	err       error
+}
+
+func newBoard(market string, format oddsapi.OddsFormat, highlight time.Duration, color bool) *board {
+	return &board{
+		market:    market,
+		format:    format,
+		highlight: highlight,
+		color:     color,
+		moves:     make(map[moveKey]*move),
+	}
+}
+
+func (b *board) update(odds []*oddsapi.Odds, resp *oddsapi.Response, at time.Time) {
+	b.odds = odds
+	b.fetchedAt = at
+	if q := resp.Quota(); q.Known {
+		b.quota = q
+	}
+	b.err = nil
+}
+
+func (b *board) apply(c *poller.Change) {
+	switch c.Kind {
+	case poller.Error, poller.QuotaExhausted:
+		b.err = c.Err
+	case poller.OutcomeChanged:
+		if c.Market != b.market || !c.Outcome.PriceMoved() {
+			return
+		}
+		key := moveKey{eventId: c.EventId, bookMaker: c.BookMaker, outcome: c.Outcome.Outcome}
+		b.moves[key] = &move{lengthened: c.Outcome.PriceChange > 0, at: c.Time}
+	}
+}
+
+// expire forgets moves older than highlight, reporting whether any were
+// still colored on the board
+func (b *board) expire(now time.Time) bool {
+	expired := false
+	for k, mv := range b.moves {
+		if now.Sub(mv.at) >= b.highlight {
+			delete(b.moves, k)
+			expired = true
+		}
+	}
+	return expired
+}
+
+type boardCell struct {
+	text    string
+	style   string
+	decimal float64
+}
+
+type boardRow struct {
+	label string
+	cells []*boardCell
+}
+
+func (b *board) bookMakers() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, o := range b.odds {
+		for _, bm := range o.BookMakers {
+			if !seen[bm.Key] {
+				seen[bm.Key] = true
+				keys = append(keys, bm.Key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// rows builds one row per event outcome and point, so books hanging a
+// different spread or total are not compared against each other
+func (b *board) rows(books []string, now time.Time) []*boardRow {
+	events := make([]*oddsapi.Odds, len(b.odds))
+	copy(events, b.odds)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CommenceTime < events[j].CommenceTime
+	})
+
+	column := make(map[string]int, len(books))
+	for i, k := range books {
+		column[k] = i
+	}
+
+	var rows []*boardRow
+	for _, e := range events {
+		rows = append(rows, &boardRow{label: fmt.Sprintf("%s @ %s  %s", e.AwayTeam, e.HomeTeam, e.CommenceTime)})
+
+		var order []string
+		byLine := make(map[string]*boardRow)
+		best := make(map[string]float64)
+		for _, bm := range e.BookMakers {
+			for _, m := range bm.Markets {
+				if m.Key != b.market {
+					continue
+				}
+				for _, o := range m.Outcomes {
+					label := o.Name
+					if o.Point != nil {
+						label += " " + formatFloat(*o.Point)
+					}
+					row, ok := byLine[label]
+					if !ok {
+						row = &boardRow{label: "  " + label, cells: make([]*boardCell, len(books))}
+						byLine[label] = row
+						order = append(order, label)
+					}
+					decimal, err := b.format.ToDecimal(o.Price)
+					if err != nil {
+						continue
+					}
+					cell := &boardCell{text: formatFloat(o.Price), decimal: decimal}
+					if mv, ok := b.moves[moveKey{e.Id, bm.Key, o.Name}]; ok && now.Sub(mv.at) < b.highlight {
+						if mv.lengthened {
+							cell.style, cell.text = ansiGreen, cell.text+"+"
+						} else {
+							cell.style, cell.text = ansiRed, cell.text+"-"
+						}
+					}
+					row.cells[column[bm.Key]] = cell
+					best[label] = max(best[label], decimal)
+				}
+			}
+		}
+
+		for _, label := range order {
+			row := byLine[label]
+			for _, cell := range row.cells {
+				if cell != nil && cell.decimal == best[label] {
+					cell.style += ansiBold
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func (b *board) render(w io.Writer, now time.Time) error {
+	books := b.bookMakers()
+	rows := b.rows(books, now)
+
+	labelWidth := 0
+	for _, r := range rows {
+		if r.cells != nil {
+			labelWidth = max(labelWidth, utf8.RuneCountInString(r.label))
+		}
+	}
+	widths := make([]int, len(books))
+	for i, k := range books {
+		widths[i] = len(k)
+		for _, r := range rows {
+			if i < len(r.cells) && r.cells[i] != nil {
+				widths[i] = max(widths[i], len(r.cells[i].text))
+			}
+		}
+	}
+
+	var sb strings.Builder
+	if b.color {
+		sb.WriteString(ansiHidden + ansiHome + ansiClear)
+	}
+	sb.WriteString(b.paint(ansiDim, fmt.Sprintf("%s  updated %s  quota %d remaining",
+		b.market, b.fetchedAt.Format(time.TimeOnly), b.quota.Remaining)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(pad("", labelWidth))
+	for i, k := range books {
+		sb.WriteString("  " + pad(k, widths[i]))
+	}
+	sb.WriteString("\n")
+	for _, r := range rows {
+		if r.cells == nil {
+			sb.WriteString(b.paint(ansiBold, r.label) + "\n")
+			continue
+		}
+		sb.WriteString(pad(r.label, labelWidth))
+		for i, cell := range r.cells {
+			sb.WriteString("  ")
+			if cell == nil {
+				sb.WriteString(pad("", widths[i]))
+				continue
+			}
+			sb.WriteString(strings.Repeat(" ", widths[i]-len(cell.text)))
+			sb.WriteString(b.paint(cell.style, cell.text))
+		}
+		sb.WriteString("\n")
+	}
+	if b.err != nil {
+		sb.WriteString("\n" + b.paint(ansiRed, b.err.Error()) + "\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (b *board) paint(style, s string) string {
+	if !b.color || style == "" {
+		return s
+	}
+	return style + s + ansiReset
+}
+
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"oddsapi"
+	"oddsapi/diff"
+	"oddsapi/poller"
+	"strings"
+	"testing"
+	"time"
+)
+
+func boardOdds() []*oddsapi.Odds {
+	market := func(home, away float64) []*oddsapi.Market {
+		return []*oddsapi.Market{{Key: "h2h", Outcomes: []*oddsapi.Outcome{
+			{Name: "Home", Price: home},
+			{Name: "Away", Price: away},
+		}}}
+	}
+	return []*oddsapi.Odds{{
+		Id:           "event1",
+		CommenceTime: "2024-01-01T00:00:00Z",
+		HomeTeam:     "Home",
+		AwayTeam:     "Away",
+		BookMakers: []*oddsapi.BookMaker{
+			{Key: "book1", Markets: market(1.9, 2.0)},
+			{Key: "book2", Markets: market(1.95, 1.9)},
+		},
+	}}
+}
+
+func TestBoard_Render(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newBoard("h2h", oddsapi.DecimalOddsFormat, time.Minute, true)
+	b.update(boardOdds(), nil, now)
+	b.apply(&poller.Change{
+		Kind:      poller.OutcomeChanged,
+		Time:      now,
+		EventId:   "event1",
+		BookMaker: "book1",
+		Market:    "h2h",
+		Outcome:   &diff.OutcomeChange{Kind: diff.OutcomeMoved, Outcome: "Away", PriceChange: 0.1},
+	})
+
+	var buf bytes.Buffer
+	if err := b.render(&buf, now); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, ansiBold+"1.95"+ansiReset) {
+		t.Errorf("expected the best home price to be bold:\n%q", out)
+	}
+	if !strings.Contains(out, ansiGreen+ansiBold+"2+"+ansiReset) {
+		t.Errorf("expected the lengthened away price to be green and bold:\n%q", out)
+	}
+
+	if b.expire(now.Add(30 * time.Second)) {
+		t.Error("expected the move to still be highlighted")
+	}
+	if !b.expire(now.Add(time.Minute)) {
+		t.Error("expected the move to expire")
+	}
+
+	buf.Reset()
+	b.color = false
+	if err := b.render(&buf, now); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "\x1b") {
+		t.Errorf("expected no escape codes without color:\n%q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "  Away") {
+		t.Errorf("expected a row per outcome:\n%s", buf.String())
+	}
+}
+
+func TestWatch_ShowsCursor(t *testing.T) {
+	var buf bytes.Buffer
+	b := newBoard("h2h", oddsapi.DecimalOddsFormat, time.Minute, true)
+	snapshots := make(chan *snapshot)
+	changes := make(chan *poller.Change)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	close(changes)
+
+	if err := watch(ctx, b, &output{w: &buf, format: formatTable}, snapshots, changes); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(buf.String(), ansiShown) {
+		t.Errorf("expected the cursor to be shown when watch returns:\n%q", buf.String())
+	}
+}
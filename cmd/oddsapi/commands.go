@@ -0,0 +1,291 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"oddsapi"
+	"strconv"
+	"strings"
+)
+
+var commands = []*command{
+	{name: "sports", usage: "list the sports in season, or all with -all", flags: sportsFlags},
+	{name: "events", usage: "list the events of a sport", flags: eventsFlags},
+	{name: "odds", usage: "list the odds of a sport's events", flags: oddsFlags},
+	{name: "event-odds", usage: "show the odds of a single event", flags: eventOddsFlags},
+	{name: "scores", usage: "list live and recently completed scores", flags: scoresFlags},
+	{name: "watch", usage: "poll the odds of a sport into a live board", flags: watchFlags},
+	{name: "quota", usage: "show the remaining request quota", flags: quotaFlags},
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func regions(s string) []oddsapi.Region {
+	var r []oddsapi.Region
+	for _, v := range splitList(s) {
+		r = append(r, oddsapi.Region(v))
+	}
+	return r
+}
+
+func markets(s string) []oddsapi.MarketKey {
+	var m []oddsapi.MarketKey
+	for _, v := range splitList(s) {
+		m = append(m, oddsapi.MarketKey(v))
+	}
+	return m
+}
+
+func oddsFormat(s string) (oddsapi.OddsFormat, error) {
+	o := oddsapi.OddsFormat(s)
+	if !o.Valid() {
+		return "", fmt.Errorf("invalid odds format: %s", s)
+	}
+	return o, nil
+}
+
+// checkResponse reports the status of a non 200 response rather than the
+// decoding error of its body
+func checkResponse(resp *oddsapi.Response, err error) error {
+	if resp != nil && resp.StatusCode != 200 {
+		return fmt.Errorf("bad response %s", resp.Status)
+	}
+	return err
+}
+
+func sportsFlags(fs *flag.FlagSet) func(s *session) error {
+	all := fs.Bool("all", false, "include out of season sports")
+	return func(s *session) error {
+		sports, resp, err := s.SportsService.GetSports()
+		if err = checkResponse(resp, err); err != nil {
+			return err
+		}
+		if !*all {
+			active := sports[:0]
+			for _, s := range sports {
+				if s.Active {
+					active = append(active, s)
+				}
+			}
+			sports = active
+		}
+		return s.out.sports(sports)
+	}
+}
+
+type commenceFlags struct {
+	from *string
+	to   *string
+}
+
+func addCommenceFlags(fs *flag.FlagSet) *commenceFlags {
+	return &commenceFlags{
+		from: fs.String("from", "", "only events commencing on or after this RFC3339 time"),
+		to:   fs.String("to", "", "only events commencing on or before this RFC3339 time"),
+	}
+}
+
+func eventsFlags(fs *flag.FlagSet) func(s *session) error {
+	sport := fs.String("sport", "", "sport key, required")
+	eventIds := fs.String("event-ids", "", "comma-separated event ids")
+	window := addCommenceFlags(fs)
+	return func(s *session) error {
+		if *sport == "" {
+			return errors.New("-sport is required")
+		}
+		params := s.EventService.NewEventParams(*sport)
+		var err error
+		if ids := splitList(*eventIds); ids != nil {
+			params.SetEventIds(ids...)
+		}
+		if *window.from != "" {
+			if err = params.SetCommenceTimeFromISO(*window.from); err != nil {
+				return err
+			}
+		}
+		if *window.to != "" {
+			if err = params.SetCommenceTimeToISO(*window.to); err != nil {
+				return err
+			}
+		}
+
+		events, resp, err := s.EventService.GetEvents(params)
+		if err = checkResponse(resp, err); err != nil {
+			return err
+		}
+		return s.out.events(events)
+	}
+}
+
+// addOddsParamFlags adds the flags of an odds request, returning a function
+// that builds its params once the flags are parsed. Flags left blank fall
+// back to the profile and then to the API defaults.
+func addOddsParamFlags(fs *flag.FlagSet) func(s *session) (*oddsapi.OddsParams, error) {
+	sport := fs.String("sport", oddsapi.DefaultSports, "sport key")
+	regionList := fs.String("regions", "", "comma-separated regions (default from profile, or us)")
+	marketList := fs.String("markets", "", "comma-separated markets (default from profile, or h2h)")
+	bookmakers := fs.String("bookmakers", "", "comma-separated bookmakers (default from profile)")
+	eventIds := fs.String("event-ids", "", "comma-separated event ids")
+	prices := fs.String("odds-format", "", "decimal or american (default from profile, or decimal)")
+	window := addCommenceFlags(fs)
+	return func(s *session) (*oddsapi.OddsParams, error) {
+		params := s.OddsService.NewOddsParams(*sport)
+		err := params.SetRegions(regions(*regionList)...)
+		if err != nil {
+			return nil, err
+		}
+		if err = params.SetMarkets(markets(*marketList)...); err != nil {
+			return nil, err
+		}
+		if *prices != "" {
+			if params.OddsFormat, err = oddsFormat(*prices); err != nil {
+				return nil, err
+			}
+		}
+		if b := splitList(*bookmakers); b != nil {
+			params.SetBookmakers(b...)
+		}
+		if ids := splitList(*eventIds); ids != nil {
+			params.SetEventIds(ids...)
+		}
+		if *window.from != "" {
+			if err = params.SetCommenceTimeFromISO(*window.from); err != nil {
+				return nil, err
+			}
+		}
+		if *window.to != "" {
+			if err = params.SetCommenceTimeToISO(*window.to); err != nil {
+				return nil, err
+			}
+		}
+
+		if err = s.profile.ApplyOddsParams(params); err != nil {
+			return nil, err
+		}
+		if params.Region == "" {
+			params.Region = oddsapi.DefaultRegion.String()
+		}
+		if params.Markets == "" {
+			params.Markets = oddsapi.MarketH2H.String()
+		}
+		params.ValidateOddsFormat()
+		return params, nil
+	}
+}
+
+func oddsFlags(fs *flag.FlagSet) func(s *session) error {
+	newParams := addOddsParamFlags(fs)
+	return func(s *session) error {
+		params, err := newParams(s)
+		if err != nil {
+			return err
+		}
+		odds, resp, err := s.OddsService.GetOdds(params)
+		if err = checkResponse(resp, err); err != nil {
+			return err
+		}
+		return s.out.odds(odds)
+	}
+}
+
+func eventOddsFlags(fs *flag.FlagSet) func(s *session) error {
+	sport := fs.String("sport", "", "sport key, required")
+	event := fs.String("event", "", "event id, required")
+	regionList := fs.String("regions", "", "comma-separated regions (default from profile, or us)")
+	marketList := fs.String("markets", "", "comma-separated markets (default from profile, or h2h)")
+	bookmakers := fs.String("bookmakers", "", "comma-separated bookmakers (default from profile)")
+	prices := fs.String("odds-format", "", "decimal or american (default from profile, or decimal)")
+	return func(s *session) error {
+		if *sport == "" || *event == "" {
+			return errors.New("-sport and -event are required")
+		}
+		params := s.EventOddsService.NewParams(*sport, *event)
+		if r := regions(*regionList); r != nil {
+			if err := params.SetRegions(r...); err != nil {
+				return err
+			}
+		}
+		if m := markets(*marketList); m != nil {
+			if err := params.SetMarkets(m...); err != nil {
+				return err
+			}
+		}
+		if *prices != "" {
+			o, err := oddsFormat(*prices)
+			if err != nil {
+				return err
+			}
+			params.SetOddsFormat(o)
+		}
+		if b := splitList(*bookmakers); b != nil {
+			params.SetBookmakers(b...)
+		}
+		if err := s.profile.ApplyEventOddsParams(params); err != nil {
+			return err
+		}
+
+		odds, resp, err := s.EventOddsService.GetOdds(params)
+		if err = checkResponse(resp, err); err != nil {
+			return err
+		}
+		return s.out.odds([]*oddsapi.Odds{odds})
+	}
+}
+
+func scoresFlags(fs *flag.FlagSet) func(s *session) error {
+	sport := fs.String("sport", "", "sport key, required")
+	daysFrom := fs.Int("days-from", 0, "include games completed up to this many days ago, 1 to 3")
+	eventIds := fs.String("event-ids", "", "comma-separated event ids")
+	return func(s *session) error {
+		if *sport == "" {
+			return errors.New("-sport is required")
+		}
+		params := s.ScoresService.NewScoresParams(*sport)
+		err := params.SetDaysFrom(*daysFrom)
+		if err != nil {
+			return err
+		}
+		if ids := splitList(*eventIds); ids != nil {
+			params.SetEventIds(ids...)
+		}
+
+		scores, resp, err := s.ScoresService.GetScores(params)
+		if err = checkResponse(resp, err); err != nil {
+			return err
+		}
+		return s.out.scores(scores)
+	}
+}
+
+// quotaFlags requests the sports list, which does not count against the
+// quota, to read the usage headers
+func quotaFlags(_ *flag.FlagSet) func(s *session) error {
+	return func(s *session) error {
+		_, resp, err := s.SportsService.GetSports()
+		if err = checkResponse(resp, err); err != nil {
+			return err
+		}
+		q := resp.Quota()
+		if !q.Known {
+			return errors.New("response did not include quota headers")
+		}
+		return s.out.quota(q)
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Command oddsapi queries the Odds API from the command line.
+//
+//	oddsapi <command> [flags]
+//
+// Settings come from a profile in the config file (see oddsapi.LoadConfig),
+// overridden by ODDSAPI_ environment variables and then by flags. API_KEY is
+// still read when no other api key is set.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"oddsapi"
+	"os"
+)
+
+type command struct {
+	name  string
+	usage string
+	flags func(fs *flag.FlagSet) func(s *session) error
+}
+
+// session is what a command runs with once the global flags are resolved
+type session struct {
+	*oddsapi.Client
+	profile *oddsapi.Profile
+	out     *output
+}
+
+func lookup(name string) (*command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "help" {
+		usage(stderr)
+		return 2
+	}
+
+	cmd, ok := lookup(args[0])
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: oddsapi %s [flags]\n\n%s\n\n", cmd.name, cmd.usage)
+		flags.PrintDefaults()
+	}
+	configPath := flags.String("config", "", "config file, defaults to $ODDSAPI_CONFIG or oddsapi/config.yaml in the user config dir")
+	profileName := flags.String("profile", "", "config profile, defaults to $ODDSAPI_PROFILE or the config's default")
+	apiKey := flags.String("key", "", "api key, overrides the profile")
+	baseUrl := flags.String("base-url", "", "api base url, overrides the profile")
+	format := flags.String("o", string(formatTable), "output format: table, json or csv")
+	exec := cmd.flags(flags)
+	if err := flags.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	out, err := newOutput(stdout, outputFormat(*format))
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 2
+	}
+	profile, err := loadProfile(*configPath, *profileName)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if *apiKey != "" {
+		profile.ApiKey = *apiKey
+	}
+	if *baseUrl != "" {
+		profile.BaseUrl = *baseUrl
+	}
+	if profile.ApiKey == "" {
+		profile.ApiKey = os.Getenv("API_KEY")
+	}
+	if profile.ApiKey == "" {
+		_, _ = fmt.Fprintln(stderr, "no api key provided, set -key, a profile api_key or ODDSAPI_API_KEY")
+		return 2
+	}
+
+	client, err := profile.NewClient()
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "error creating client: %s\n", err)
+		return 1
+	}
+	if err = exec(&session{Client: client, profile: profile, out: out}); err != nil {
+		_, _ = fmt.Fprintf(stderr, "%s: %s\n", cmd.name, err)
+		return 1
+	}
+	return 0
+}
+
+// loadProfile reads the profile from the config file. Without -config a
+// missing default config file is not an error.
+func loadProfile(path, name string) (*oddsapi.Profile, error) {
+	explicit := path != ""
+	if !explicit {
+		var err error
+		if path, err = oddsapi.DefaultConfigPath(); err != nil {
+			return nil, err
+		}
+		explicit = os.Getenv(oddsapi.EnvConfig) != ""
+	}
+
+	config, err := oddsapi.LoadConfig(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		config, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return config.Profile(name)
+}
+
+func usage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "usage: oddsapi <command> [flags]\n\ncommands:")
+	for _, c := range commands {
+		_, _ = fmt.Fprintf(w, "  %-12s %s\n", c.name, c.usage)
+	}
+	_, _ = fmt.Fprintln(w, "\nrun 'oddsapi <command> -h' for the flags of a command")
+}
@@ -0,0 +1,155 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"oddsapi"
+	"oddsapi/export"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+type outputFormat string
+
+const (
+	formatTable outputFormat = "table"
+	formatJSON  outputFormat = "json"
+	formatCSV   outputFormat = "csv"
+)
+
+func (o outputFormat) Valid() bool {
+	switch o {
+	case formatTable, formatJSON, formatCSV:
+		return true
+	}
+	return false
+}
+
+type output struct {
+	w      io.Writer
+	format outputFormat
+}
+
+func newOutput(w io.Writer, format outputFormat) (*output, error) {
+	if !format.Valid() {
+		return nil, fmt.Errorf("invalid output format: %s", format)
+	}
+	return &output{w: w, format: format}, nil
+}
+
+// write prints data as JSON, or the header and rows as a table or CSV
+func (o *output) write(data any, header []string, rows [][]string) error {
+	switch o.format {
+	case formatJSON:
+		enc := json.NewEncoder(o.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(data)
+	case formatCSV:
+		w := csv.NewWriter(o.w)
+		if err := w.Write(header); err != nil {
+			return err
+		}
+		if err := w.WriteAll(rows); err != nil {
+			return err
+		}
+		return w.Error()
+	}
+
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	upper := make([]string, len(header))
+	for i, h := range header {
+		upper[i] = strings.ToUpper(h)
+	}
+	_, _ = fmt.Fprintln(tw, strings.Join(upper, "\t"))
+	for _, r := range rows {
+		_, _ = fmt.Fprintln(tw, strings.Join(r, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (o *output) sports(sports []*oddsapi.Sports) error {
+	rows := make([][]string, len(sports))
+	for i, s := range sports {
+		rows[i] = []string{s.Key, s.Group, s.Title, s.Description, strconv.FormatBool(s.Active), strconv.FormatBool(s.HasOutrights)}
+	}
+	return o.write(sports, []string{"key", "group", "title", "description", "active", "has_outrights"}, rows)
+}
+
+func (o *output) events(events []*oddsapi.Event) error {
+	rows := make([][]string, len(events))
+	for i, e := range events {
+		rows[i] = []string{e.Id, e.SportKey, e.CommenceTime, e.HomeTeam, e.AwayTeam}
+	}
+	return o.write(events, []string{"id", "sport_key", "commence_time", "home_team", "away_team"}, rows)
+}
+
+var oddsTableColumns = []string{
+	"commence_time", "home_team", "away_team", "bookmaker_key",
+	"market_key", "outcome_name", "outcome_point", "outcome_price",
+}
+
+// odds writes CSV with every export column so the file can be read back
+// with export.NewReader. The table only shows the columns that fit a terminal.
+func (o *output) odds(odds []*oddsapi.Odds) error {
+	switch o.format {
+	case formatJSON:
+		return o.write(odds, nil, nil)
+	case formatCSV:
+		w, err := export.NewWriter(o.w, export.CSV)
+		if err != nil {
+			return err
+		}
+		return w.Write(odds)
+	}
+
+	flat := export.Flatten(odds)
+	rows := make([][]string, 0, len(flat))
+	for _, r := range flat {
+		if r.Outcome == nil {
+			continue
+		}
+		var point string
+		if r.Outcome.Point != nil {
+			point = formatFloat(*r.Outcome.Point)
+		}
+		rows = append(rows, []string{
+			r.Odds.CommenceTime, r.Odds.HomeTeam, r.Odds.AwayTeam, r.BookMaker.Key,
+			r.Market.Key, r.Outcome.Name, point, formatFloat(r.Outcome.Price),
+		})
+	}
+	return o.write(odds, oddsTableColumns, rows)
+}
+
+func (o *output) scores(scores []*oddsapi.Score) error {
+	points := func(s *oddsapi.Score, team string) string {
+		for _, ts := range s.Scores {
+			if ts.Name == team {
+				return ts.Score
+			}
+		}
+		return ""
+	}
+	rows := make([][]string, len(scores))
+	for i, s := range scores {
+		rows[i] = []string{
+			s.Id, s.CommenceTime, s.HomeTeam, points(s, s.HomeTeam),
+			s.AwayTeam, points(s, s.AwayTeam), strconv.FormatBool(s.Completed),
+		}
+	}
+	return o.write(scores, []string{"id", "commence_time", "home_team", "home_score", "away_team", "away_score", "completed"}, rows)
+}
+
+func (o *output) quota(q oddsapi.Quota) error {
+	data := struct {
+		Remaining int `json:"remaining"`
+		Used      int `json:"used"`
+	}{q.Remaining, q.Used}
+	rows := [][]string{{strconv.Itoa(q.Remaining), strconv.Itoa(q.Used)}}
+	return o.write(data, []string{"remaining", "used"}, rows)
+}
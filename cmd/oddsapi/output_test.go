@@ -0,0 +1,173 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"oddsapi"
+	"oddsapi/export"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sampleOdds() []*oddsapi.Odds {
+	point := -3.5
+	return []*oddsapi.Odds{{
+		Id:           "event1",
+		SportKey:     "basketball_nba",
+		CommenceTime: "2024-01-01T00:00:00Z",
+		HomeTeam:     "Home",
+		AwayTeam:     "Away",
+		BookMakers: []*oddsapi.BookMaker{{
+			Key: "book1",
+			Markets: []*oddsapi.Market{{Key: "spreads", Outcomes: []*oddsapi.Outcome{
+				{Name: "Home", Price: 1.91, Point: &point},
+			}}},
+		}},
+	}}
+}
+
+func TestOutput_Odds(t *testing.T) {
+	var buf bytes.Buffer
+	out, err := newOutput(&buf, formatTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = out.odds(sampleOdds()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "COMMENCE_TIME") {
+		t.Fatalf("unexpected table %q", buf.String())
+	}
+	if fields := strings.Fields(lines[1]); fields[6] != "-3.5" || fields[7] != "1.91" {
+		t.Errorf("unexpected row %v", fields)
+	}
+
+	buf.Reset()
+	out.format = formatCSV
+	if err = out.odds(sampleOdds()); err != nil {
+		t.Fatal(err)
+	}
+	r, err := export.NewReader(&buf, export.CSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	odds, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(odds) != 1 || odds[0].BookMakers[0].Markets[0].Outcomes[0].Price != 1.91 {
+		t.Errorf("unexpected odds read back from csv")
+	}
+
+	buf.Reset()
+	out.format = formatJSON
+	if err = out.odds(sampleOdds()); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []*oddsapi.Odds
+	if err = json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 1 || decoded[0].Id != "event1" {
+		t.Errorf("unexpected json %s", buf.String())
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(oddsapi.EnvConfig, path)
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"nope"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for an unknown command, got %d", code)
+	}
+	if code := run([]string{"odds", "-o", "xml", "-key", "k"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for an invalid output format, got %d", code)
+	}
+	if code := run([]string{"events", "-key", "k"}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit code 1 without a sport, got %d", code)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	list := splitList(" us, uk,,eu ")
+	if strings.Join(list, "|") != "us|uk|eu" {
+		t.Errorf("unexpected list %v", list)
+	}
+	if splitList("") != nil {
+		t.Error("expected nil for an empty list")
+	}
+}
+
+func TestRun_Profile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v4/sports" || r.URL.Query().Get("apiKey") != "profile-key" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"key":"basketball_nba","active":true,"group":"Basketball","title":"NBA"}]`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	config := "[profiles.test]\napi_key = \"profile-key\"\nbase_url = \"" + server.URL + "\"\n"
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(oddsapi.EnvApiKey, "")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"sports", "-config", path, "-profile", "test", "-o", "csv"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "basketball_nba,Basketball,NBA") {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"sports", "-config", path, "-profile", "test", "-key", "other"}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected the -key flag to override the profile, got exit code %d", code)
+	}
+}
+
+func TestRun_CommenceWindow(t *testing.T) {
+	queries := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.RawQuery
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	config := "[profiles.test]\napi_key = \"key\"\nbase_url = \"" + server.URL + "\"\n"
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, command := range []string{"events", "odds"} {
+		var stdout, stderr bytes.Buffer
+		args := []string{command, "-config", path, "-profile", "test", "-sport", "basketball_nba",
+			"-from", "2024-01-01T00:00:00Z", "-to", "2024-01-02T00:00:00Z"}
+		if code := run(args, &stdout, &stderr); code != 0 {
+			t.Fatalf("expected exit code 0 for %s, got %d: %s", command, code, stderr.String())
+		}
+		q, err := url.ParseQuery(<-queries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Get("commenceTimeFrom") != "2024-01-01T00:00:00Z" || q.Get("commenceTimeTo") != "2024-01-02T00:00:00Z" {
+			t.Errorf("expected %s to send the commence time window, got %s", command, q.Encode())
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"oddsapi"
+	"oddsapi/poller"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultHighlight = 5 * time.Minute
+	redrawInterval   = time.Second
+)
+
+type snapshot struct {
+	odds []*oddsapi.Odds
+	resp *oddsapi.Response
+	at   time.Time
+}
+
+// watchFlags polls odds into a board until interrupted. Every poll goes
+// through the client's rate limiter and costs the same quota as the odds
+// command, so -interval and -min-remaining bound the daily spend.
+func watchFlags(fs *flag.FlagSet) func(s *session) error {
+	newParams := addOddsParamFlags(fs)
+	interval := fs.Duration("interval", poller.DefaultInterval, "time between polls")
+	highlight := fs.Duration("highlight", defaultHighlight, "how long price moves stay colored")
+	minRemaining := fs.Int("min-remaining", 0, "stop once the remaining quota drops to this or lower")
+	noColor := fs.Bool("no-color", os.Getenv("NO_COLOR") != "", "disable colors and screen clearing")
+	return func(s *session) error {
+		if s.out.format != formatTable {
+			return errors.New("watch only supports table output")
+		}
+		params, err := newParams(s)
+		if err != nil {
+			return err
+		}
+		market := splitList(params.Markets)[0]
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		snapshots := make(chan *snapshot)
+		fetch := poller.OddsFetcher(s.OddsService, params)
+		p := poller.New(func(ctx context.Context) ([]*oddsapi.Odds, *oddsapi.Response, error) {
+			odds, resp, err := fetch(ctx)
+			if err = checkResponse(resp, err); err != nil {
+				return nil, resp, err
+			}
+			select {
+			case <-ctx.Done():
+				return nil, resp, ctx.Err()
+			case snapshots <- &snapshot{odds: odds, resp: resp, at: time.Now()}:
+			}
+			return odds, resp, nil
+		}, *interval)
+		p.MinRemaining = *minRemaining
+
+		b := newBoard(market, params.OddsFormat, *highlight, !*noColor)
+		return watch(ctx, b, s.out, snapshots, p.Start(ctx))
+	}
+}
+
+func watch(ctx context.Context, b *board, out *output, snapshots <-chan *snapshot, changes <-chan *poller.Change) error {
+	ticker := time.NewTicker(redrawInterval)
+	defer ticker.Stop()
+	if b.color {
+		// render hides the cursor, so show it again however watch ends
+		defer func() { _, _ = io.WriteString(out.w, ansiShown) }()
+	}
+
+	var (
+		dirty     bool
+		exhausted error
+	)
+	for {
+		select {
+		case s := <-snapshots:
+			b.update(s.odds, s.resp, s.at)
+			dirty = true
+		case c, ok := <-changes:
+			if !ok {
+				if ctx.Err() == nil && b.odds != nil {
+					_ = b.render(out.w, time.Now())
+				}
+				return exhausted
+			}
+			if c.Kind == poller.QuotaExhausted {
+				exhausted = c.Err
+			}
+			b.apply(c)
+			dirty = true
+		case now := <-ticker.C:
+			if b.expire(now) {
+				dirty = true
+			}
+			if dirty && b.odds != nil {
+				if err := b.render(out.w, now); err != nil {
+					return err
+				}
+				dirty = false
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"context"
+	"errors"
+	"oddsapi/internal/testserver"
+	"sync"
+	"testing"
+)
+
+func TestClient_Coalesce(t *testing.T) {
+	server := testserver.New(t)
+	server.Release = make(chan struct{})
+
+	c, err := NewClient("key", 100, SetBaseUrl(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const callers = 5
+	var joined, wg sync.WaitGroup
+	joined.Add(callers)
+	c.joined = joined.Done
+
+	results := make([][]*Odds, callers)
+	responses := make([]*Response, callers)
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func(i int) {
+			defer wg.Done()
+			params := c.OddsService.NewOddsParams("basketball_nba")
+			_ = params.SetRegions(RegionUs)
+			odds, resp, err := c.OddsService.GetOdds(params)
+			if err != nil {
+				t.Error(err)
+			}
+			results[i], responses[i] = odds, resp
+		}(i)
+	}
+
+	// upstream answers only once every caller waits on the shared request
+	joined.Wait()
+	close(server.Release)
+	wg.Wait()
+
+	if n := server.Hits.Load(); n != 1 {
+		t.Fatalf("expected 1 upstream request, got %d", n)
+	}
+
+	results[0][0].BookMakers[0].Key = "mutated"
+	responses[0].Header.Set(headerRequestsRemaining, "0")
+	for i := 1; i < callers; i++ {
+		if results[i][0].BookMakers[0].Key != "book1" {
+			t.Errorf("expected caller %d to have its own copy of the data", i)
+		}
+		if responses[i].Quota().Remaining != 100 {
+			t.Errorf("expected caller %d to have its own copy of the headers", i)
+		}
+	}
+}
+
+func TestClient_CoalesceCancelledLeader(t *testing.T) {
+	server := testserver.New(t)
+	server.Release = make(chan struct{})
+
+	c, err := NewClient("key", 100, SetBaseUrl(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := c.OddsService.NewOddsParams("basketball_nba").BuildPath(c.GetBaseUrl())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	joined := make(chan struct{}, 2)
+	c.joined = func() { joined <- struct{}{} }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader, err := c.NewGetRequest(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := c.Do(leader.WithContext(ctx), &[]*Odds{})
+		leaderErr <- err
+	}()
+	<-joined
+	cancel()
+	if err = <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the leader to stop waiting once cancelled, got %v", err)
+	}
+
+	follower, err := c.NewGetRequest(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	var odds []*Odds
+	go func() {
+		_, err := c.Do(follower, &odds)
+		done <- err
+	}()
+	<-joined
+	close(server.Release)
+	if err = <-done; err != nil {
+		t.Fatalf("expected the shared request to survive the leader's cancellation, got %v", err)
+	}
+	if len(odds) != 1 || server.Hits.Load() != 1 {
+		t.Errorf("expected the follower to share the single upstream request, got %d requests", server.Hits.Load())
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultProfileName = "default"
+	DefaultRateLimit   = 10
+)
+
+// Environment variables that override the values of a profile
+const (
+	EnvConfig           = "ODDSAPI_CONFIG"
+	EnvProfile          = "ODDSAPI_PROFILE"
+	EnvApiKey           = "ODDSAPI_API_KEY"
+	EnvBaseUrl          = "ODDSAPI_BASE_URL"
+	EnvRegions          = "ODDSAPI_REGIONS"
+	EnvMarkets          = "ODDSAPI_MARKETS"
+	EnvBookmakers       = "ODDSAPI_BOOKMAKERS"
+	EnvOddsFormat       = "ODDSAPI_ODDS_FORMAT"
+	EnvRateLimitPercent = "ODDSAPI_RATE_LIMIT_PERCENT"
+)
+
+// Profile holds the client settings and default request values of one
+// account or environment.
+type Profile struct {
+	ApiKey  string `yaml:"api_key" toml:"api_key"`
+	BaseUrl string `yaml:"base_url" toml:"base_url"`
+
+	Regions    []Region    `yaml:"regions" toml:"regions"`
+	Markets    []MarketKey `yaml:"markets" toml:"markets"`
+	Bookmakers []string    `yaml:"bookmakers" toml:"bookmakers"`
+	OddsFormat OddsFormat  `yaml:"odds_format" toml:"odds_format"`
+
+	// RateLimit is the number of requests per second the account allows,
+	// and RateLimitPercent the share of it the client uses
+	RateLimit        int     `yaml:"rate_limit" toml:"rate_limit"`
+	RateLimitPercent float64 `yaml:"rate_limit_percent" toml:"rate_limit_percent"`
+}
+
+type Config struct {
+	DefaultProfile string              `yaml:"default_profile" toml:"default_profile"`
+	Profiles       map[string]*Profile `yaml:"profiles" toml:"profiles"`
+}
+
+// DefaultConfigPath is config.yaml in the oddsapi directory of the user's
+// config directory, unless ODDSAPI_CONFIG is set.
+func DefaultConfigPath() (string, error) {
+	if p := os.Getenv(EnvConfig); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "oddsapi", "config.yaml"), nil
+}
+
+// LoadConfig reads a YAML or TOML config, chosen by the file extension.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Config{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, c)
+	case ".toml":
+		err = toml.Unmarshal(data, c)
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config %s: %w", path, err)
+	}
+
+	for name, p := range c.Profiles {
+		if p == nil {
+			c.Profiles[name] = &Profile{}
+			continue
+		}
+		if err = p.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid profile %s: %w", name, err)
+		}
+	}
+	return c, nil
+}
+
+// Profile returns a copy of the named profile with environment overrides
+// applied. A blank name selects ODDSAPI_PROFILE, then the config's default
+// profile, then the profile named default. A nil Config or a missing default
+// profile yields an empty profile so the environment alone can configure it.
+func (c *Config) Profile(name string) (*Profile, error) {
+	explicit := name != ""
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+		explicit = name != ""
+	}
+	if name == "" && c != nil && c.DefaultProfile != "" {
+		name = c.DefaultProfile
+		explicit = true
+	}
+	if name == "" {
+		name = DefaultProfileName
+	}
+
+	p := &Profile{}
+	if c != nil && c.Profiles[name] != nil {
+		*p = *c.Profiles[name]
+	} else if explicit {
+		return nil, fmt.Errorf("no profile named %s", name)
+	}
+
+	if err := p.ApplyEnv(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ApplyEnv overrides the profile with any ODDSAPI_ environment variables
+func (p *Profile) ApplyEnv() error {
+	if v := os.Getenv(EnvApiKey); v != "" {
+		p.ApiKey = v
+	}
+	if v := os.Getenv(EnvBaseUrl); v != "" {
+		p.BaseUrl = v
+	}
+	if v := os.Getenv(EnvRegions); v != "" {
+		p.Regions = nil
+		for _, r := range strings.Split(v, ",") {
+			p.Regions = append(p.Regions, Region(strings.TrimSpace(r)))
+		}
+	}
+	if v := os.Getenv(EnvMarkets); v != "" {
+		p.Markets = nil
+		for _, m := range strings.Split(v, ",") {
+			p.Markets = append(p.Markets, MarketKey(strings.TrimSpace(m)))
+		}
+	}
+	if v := os.Getenv(EnvBookmakers); v != "" {
+		p.Bookmakers = nil
+		for _, b := range strings.Split(v, ",") {
+			p.Bookmakers = append(p.Bookmakers, strings.TrimSpace(b))
+		}
+	}
+	if v := os.Getenv(EnvOddsFormat); v != "" {
+		p.OddsFormat = OddsFormat(v)
+	}
+	if v := os.Getenv(EnvRateLimitPercent); v != "" {
+		percent, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvRateLimitPercent, err)
+		}
+		p.RateLimitPercent = percent
+	}
+	return p.Validate()
+}
+
+func (p *Profile) Validate() error {
+	for _, r := range p.Regions {
+		if !r.Valid() {
+			return fmt.Errorf("invalid region provided: %s", r)
+		}
+	}
+	for _, m := range p.Markets {
+		if !m.Valid() {
+			return fmt.Errorf("invalid market provided: %s", m)
+		}
+	}
+	if p.OddsFormat != "" && !p.OddsFormat.Valid() {
+		return fmt.Errorf("invalid odds format: %s", p.OddsFormat)
+	}
+	if p.RateLimit < 0 {
+		return fmt.Errorf("invalid rate limit: %d", p.RateLimit)
+	}
+	if p.RateLimitPercent < 0 || p.RateLimitPercent > 1 {
+		return fmt.Errorf("rate limit percent must be between 0 and 1, got %v", p.RateLimitPercent)
+	}
+	return nil
+}
+
+func (p *Profile) ClientOptions() []ClientOption {
+	var options []ClientOption
+	if p.BaseUrl != "" {
+		options = append(options, SetBaseUrl(p.BaseUrl))
+	}
+	if p.RateLimitPercent > 0 {
+		options = append(options, SetPercentOfRateLimit(p.RateLimitPercent))
+	}
+	return options
+}
+
+// NewClient creates a client from the profile. Options are applied after
+// the profile's own, so they take precedence.
+func (p *Profile) NewClient(options ...ClientOption) (*Client, error) {
+	rateLimit := p.RateLimit
+	if rateLimit == 0 {
+		rateLimit = DefaultRateLimit
+	}
+	return NewClient(p.ApiKey, rateLimit, append(p.ClientOptions(), options...)...)
+}
+
+// ApplyOddsParams fills in the regions, markets, bookmakers and odds format
+// that are not already set on params.
+func (p *Profile) ApplyOddsParams(params *OddsParams) error {
+	if params.Region == "" {
+		if err := params.SetRegions(p.Regions...); err != nil {
+			return err
+		}
+	}
+	if params.Markets == "" {
+		if err := params.SetMarkets(p.Markets...); err != nil {
+			return err
+		}
+	}
+	if params.Bookmakers == nil && p.Bookmakers != nil {
+		params.SetBookmakers(p.Bookmakers...)
+	}
+	if params.OddsFormat == "" {
+		params.OddsFormat = p.OddsFormat
+	}
+	return nil
+}
+
+// ApplyEventOddsParams is ApplyOddsParams for a single event
+func (p *Profile) ApplyEventOddsParams(params *EventOddsParams) error {
+	if params.Region == "" && p.Regions != nil {
+		if err := params.SetRegions(p.Regions...); err != nil {
+			return err
+		}
+	}
+	if params.Markets == "" && p.Markets != nil {
+		if err := params.SetMarkets(p.Markets...); err != nil {
+			return err
+		}
+	}
+	if params.Bookmakers == nil && p.Bookmakers != nil {
+		params.SetBookmakers(p.Bookmakers...)
+	}
+	if params.OddsFormat == "" && p.OddsFormat != "" {
+		params.SetOddsFormat(p.OddsFormat)
+	}
+	return nil
+}
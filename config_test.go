@@ -0,0 +1,130 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testYamlConfig = `
+default_profile: live
+profiles:
+  live:
+    api_key: live-key
+    regions: [us, uk]
+    markets: [h2h, spreads]
+    odds_format: american
+    rate_limit_percent: 0.5
+  staging:
+    api_key: staging-key
+    base_url: http://localhost:8080
+`
+
+const testTomlConfig = `
+[profiles.live]
+api_key = "live-key"
+regions = ["us", "uk"]
+bookmakers = ["pinnacle"]
+`
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv(EnvProfile, "")
+	t.Setenv(EnvApiKey, "")
+
+	c, err := LoadConfig(writeConfig(t, "config.yaml", testYamlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := c.Profile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ApiKey != "live-key" || len(p.Regions) != 2 || p.OddsFormat != AmericanOddsFormat {
+		t.Errorf("unexpected default profile %+v", p)
+	}
+
+	client, err := p.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.maxPercentOfLimit != 0.5 || client.RateLimit != DefaultRateLimit {
+		t.Errorf("expected the profile's rate limit, got %v of %d", client.maxPercentOfLimit, client.RateLimit)
+	}
+
+	staging, err := c.Profile("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err = staging.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.GetBaseUrl().String() != "http://localhost:8080/" {
+		t.Errorf("expected the staging base url, got %s", client.GetBaseUrl())
+	}
+
+	if _, err = c.Profile("missing"); err == nil {
+		t.Error("expected an error for a missing profile")
+	}
+
+	c, err = LoadConfig(writeConfig(t, "config.toml", testTomlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p = c.Profiles["live"]; p == nil || p.Bookmakers[0] != "pinnacle" {
+		t.Errorf("unexpected toml profile %+v", p)
+	}
+
+	if _, err = LoadConfig(writeConfig(t, "config.yaml", "profiles:\n  bad:\n    regions: [mars]\n")); err == nil {
+		t.Error("expected an error for an invalid region")
+	}
+}
+
+func TestProfile_ApplyEnv(t *testing.T) {
+	t.Setenv(EnvProfile, "")
+	t.Setenv(EnvApiKey, "env-key")
+	t.Setenv(EnvMarkets, "totals")
+
+	c, err := LoadConfig(writeConfig(t, "config.yaml", testYamlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := c.Profile("live")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ApiKey != "env-key" || len(p.Markets) != 1 || p.Markets[0] != MarketTotals {
+		t.Errorf("expected environment overrides, got %+v", p)
+	}
+	if c.Profiles["live"].ApiKey != "live-key" {
+		t.Error("expected the loaded profile to be left unchanged")
+	}
+
+	var empty *Config
+	if p, err = empty.Profile(""); err != nil || p.ApiKey != "env-key" {
+		t.Errorf("expected a profile from the environment alone, got %+v, %v", p, err)
+	}
+}
+
+func TestProfile_ApplyOddsParams(t *testing.T) {
+	p := &Profile{Regions: []Region{RegionUk}, Markets: []MarketKey{MarketSpreads}, Bookmakers: []string{"pinnacle"}}
+	params := NewOddsParams("key", "basketball_nba")
+	params.Markets = MarketTotals.String()
+	if err := p.ApplyOddsParams(params); err != nil {
+		t.Fatal(err)
+	}
+	if params.Region != "uk" || params.Markets != "totals" || *params.Bookmakers != "pinnacle" {
+		t.Errorf("unexpected params %+v", params)
+	}
+}
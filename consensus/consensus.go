@@ -0,0 +1,186 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package consensus summarizes the prices every bookmaker offers on an
+// outcome into a market line and flags books that stray from it.
+package consensus
+
+import (
+	"fmt"
+	"math"
+	"oddsapi"
+	"sort"
+	"time"
+)
+
+type Options struct {
+	// OddsFormat the prices were requested in. Defaults to decimal.
+	OddsFormat oddsapi.OddsFormat
+
+	// Weights by bookmaker key for the weighted mean. Bookmakers missing
+	// from the map are weighted 1.
+	Weights map[string]float64
+}
+
+func (o Options) weight(bookMaker string) float64 {
+	if w, ok := o.Weights[bookMaker]; ok {
+		return w
+	}
+	return 1
+}
+
+type Quote struct {
+	BookMaker      string
+	BookMakerTitle string
+	Price          float64
+	DecimalPrice   float64
+	LastUpdate     time.Time
+
+	// ZScore of the decimal price against the other bookmakers
+	ZScore float64
+}
+
+type Outcome struct {
+	Name  string
+	Point *float64
+
+	// Description is the team of a team_totals outcome
+	Description string
+
+	// Stats over decimal prices
+	Stats
+
+	// Price is the weighted mean as a price in the requested OddsFormat
+	Price float64
+
+	// ImpliedProbability of the weighted mean price
+	ImpliedProbability float64
+
+	Quotes []*Quote
+}
+
+// Outliers returns quotes whose z-score is beyond threshold in either direction
+func (o *Outcome) Outliers(threshold float64) []*Quote {
+	var result []*Quote
+	for _, q := range o.Quotes {
+		if math.Abs(q.ZScore) > threshold {
+			result = append(result, q)
+		}
+	}
+	return result
+}
+
+type Market struct {
+	EventId      string
+	SportKey     string
+	HomeTeam     string
+	AwayTeam     string
+	CommenceTime string
+	Key          string
+
+	// Line is the home team's spread or the total for points markets
+	Line *float64
+
+	Outcomes []*Outcome
+}
+
+// Compute returns the consensus for every event, market and line in odds.
+func Compute(odds []*oddsapi.Odds, opts Options) ([]*Market, error) {
+	if opts.OddsFormat != "" && !opts.OddsFormat.Valid() {
+		return nil, fmt.Errorf("invalid odds format: %s", opts.OddsFormat)
+	}
+
+	var result []*Market
+	for _, event := range odds {
+		if event == nil {
+			continue
+		}
+		markets, err := computeEvent(event, opts)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, markets...)
+	}
+	return result, nil
+}
+
+func computeEvent(event *oddsapi.Odds, opts Options) ([]*Market, error) {
+	markets := make(map[string]*Market)
+	outcomes := make(map[string]map[string]*Outcome)
+	var keys []string
+	for _, b := range event.BookMakers {
+		for _, m := range b.Markets {
+			updated, _ := m.LastUpdateTime()
+			for _, o := range m.Outcomes {
+				decimal, err := opts.OddsFormat.ToDecimal(o.Price)
+				if err != nil {
+					continue
+				}
+				line := event.LinePoint(m.Key, o)
+				key := event.LineKey(m.Key, o)
+				market, ok := markets[key]
+				if !ok {
+					market = &Market{
+						EventId:      event.Id,
+						SportKey:     event.SportKey,
+						HomeTeam:     event.HomeTeam,
+						AwayTeam:     event.AwayTeam,
+						CommenceTime: event.CommenceTime,
+						Key:          m.Key,
+						Line:         line,
+					}
+					markets[key] = market
+					outcomes[key] = make(map[string]*Outcome)
+					keys = append(keys, key)
+				}
+				name := o.Name + "|" + o.Description
+				outcome, ok := outcomes[key][name]
+				if !ok {
+					outcome = &Outcome{Name: o.Name, Point: o.Point, Description: o.Description}
+					outcomes[key][name] = outcome
+					market.Outcomes = append(market.Outcomes, outcome)
+				}
+				outcome.Quotes = append(outcome.Quotes, &Quote{
+					BookMaker:      b.Key,
+					BookMakerTitle: b.Title,
+					Price:          o.Price,
+					DecimalPrice:   decimal,
+					LastUpdate:     updated,
+				})
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	result := make([]*Market, len(keys))
+	for i, key := range keys {
+		for _, o := range markets[key].Outcomes {
+			if err := o.summarize(opts); err != nil {
+				return nil, err
+			}
+		}
+		result[i] = markets[key]
+	}
+	return result, nil
+}
+
+func (o *Outcome) summarize(opts Options) error {
+	values := make([]float64, len(o.Quotes))
+	weights := make([]float64, len(o.Quotes))
+	for i, q := range o.Quotes {
+		values[i] = q.DecimalPrice
+		weights[i] = opts.weight(q.BookMaker)
+	}
+	o.Stats = newStats(values, weights)
+	for _, q := range o.Quotes {
+		q.ZScore = o.ZScore(q.DecimalPrice)
+	}
+
+	price, err := opts.OddsFormat.FromDecimal(o.WeightedMean)
+	if err != nil {
+		return err
+	}
+	o.Price = price
+	o.ImpliedProbability = oddsapi.ImpliedProbability(o.WeightedMean)
+	return nil
+}
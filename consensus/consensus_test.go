@@ -0,0 +1,133 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package consensus
+
+import (
+	"math"
+	"oddsapi"
+	"testing"
+)
+
+func h2h(key string, home, away float64) *oddsapi.BookMaker {
+	return &oddsapi.BookMaker{Key: key, Title: key, Markets: []*oddsapi.Market{{
+		Key:        "h2h",
+		LastUpdate: "2024-01-01T12:00:00Z",
+		Outcomes: []*oddsapi.Outcome{
+			{Name: "Home", Price: home},
+			{Name: "Away", Price: away},
+		},
+	}}}
+}
+
+func TestCompute(t *testing.T) {
+	odds := []*oddsapi.Odds{{
+		Id:       "event1",
+		HomeTeam: "Home",
+		AwayTeam: "Away",
+		BookMakers: []*oddsapi.BookMaker{
+			h2h("book1", 2.0, 1.8),
+			h2h("book2", 2.1, 1.75),
+			h2h("book3", 1.9, 1.9),
+			h2h("book4", 2.0, 1.8),
+		},
+	}}
+
+	result, err := Compute(odds, Options{Weights: map[string]float64{"book2": 2, "book3": 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 market, got %d", len(result))
+	}
+	if len(result[0].Outcomes) != 2 {
+		t.Fatalf("expected 2 outcomes, got %d", len(result[0].Outcomes))
+	}
+
+	home := result[0].Outcomes[0]
+	if home.Name != "Home" || home.Count != 4 {
+		t.Errorf("expected 4 home quotes, got %d for %s", home.Count, home.Name)
+	}
+	if math.Abs(home.Mean-2.0) > 1e-9 || math.Abs(home.Median-2.0) > 1e-9 {
+		t.Errorf("expected mean and median of 2.0, got %v and %v", home.Mean, home.Median)
+	}
+	if home.Min != 1.9 || home.Max != 2.1 {
+		t.Errorf("expected range 1.9-2.1, got %v-%v", home.Min, home.Max)
+	}
+
+	expectedWeighted := (2.0 + 2*2.1 + 2.0) / 4
+	if math.Abs(home.WeightedMean-expectedWeighted) > 1e-9 || home.Price != home.WeightedMean {
+		t.Errorf("expected weighted mean %v, got %v", expectedWeighted, home.WeightedMean)
+	}
+
+	expectedStdDev := math.Sqrt((0.01 + 0.01) / 4)
+	if math.Abs(home.StdDev-expectedStdDev) > 1e-9 {
+		t.Errorf("expected std dev %v, got %v", expectedStdDev, home.StdDev)
+	}
+
+	outliers := home.Outliers(1)
+	if len(outliers) != 2 {
+		t.Errorf("expected 2 outliers, got %d", len(outliers))
+	}
+}
+
+func TestCompute_SpreadsGroupByLine(t *testing.T) {
+	p := func(v float64) *float64 { return &v }
+	odds := []*oddsapi.Odds{{
+		HomeTeam: "Home",
+		AwayTeam: "Away",
+		BookMakers: []*oddsapi.BookMaker{
+			{Key: "book1", Markets: []*oddsapi.Market{{Key: "spreads", Outcomes: []*oddsapi.Outcome{
+				{Name: "Home", Price: 1.9, Point: p(-3.5)},
+				{Name: "Away", Price: 1.9, Point: p(3.5)},
+			}}}},
+			{Key: "book2", Markets: []*oddsapi.Market{{Key: "spreads", Outcomes: []*oddsapi.Outcome{
+				{Name: "Away", Price: 1.95, Point: p(3.5)},
+				{Name: "Home", Price: 1.85, Point: p(-3.5)},
+			}}}},
+			{Key: "book3", Markets: []*oddsapi.Market{{Key: "spreads", Outcomes: []*oddsapi.Outcome{
+				{Name: "Home", Price: 1.9, Point: p(-4.5)},
+				{Name: "Away", Price: 1.9, Point: p(4.5)},
+			}}}},
+		},
+	}}
+
+	result, err := Compute(odds, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(result))
+	}
+	if *result[0].Line != -3.5 || result[0].Outcomes[0].Count != 2 {
+		t.Errorf("expected 2 quotes on the -3.5 line")
+	}
+}
+
+func TestCompute_TeamTotalsByTeam(t *testing.T) {
+	p := func(v float64) *float64 { return &v }
+	totals := func(key string, home, away float64) *oddsapi.BookMaker {
+		return &oddsapi.BookMaker{Key: key, Markets: []*oddsapi.Market{{Key: "team_totals", Outcomes: []*oddsapi.Outcome{
+			{Name: "Over", Description: "Home", Price: home, Point: p(110.5)},
+			{Name: "Over", Description: "Away", Price: away, Point: p(110.5)},
+		}}}}
+	}
+	odds := []*oddsapi.Odds{{
+		HomeTeam:   "Home",
+		AwayTeam:   "Away",
+		BookMakers: []*oddsapi.BookMaker{totals("book1", 1.8, 2.0), totals("book2", 1.8, 2.0)},
+	}}
+
+	result, err := Compute(odds, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 || len(result[0].Outcomes) != 2 {
+		t.Fatalf("expected an outcome per team, got %+v", result)
+	}
+	for _, o := range result[0].Outcomes {
+		if o.Count != 2 || o.Mean != map[string]float64{"Home": 1.8, "Away": 2.0}[o.Description] {
+			t.Errorf("expected 2 quotes on the %s over, got %d at %v", o.Description, o.Count, o.Mean)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package consensus
+
+import (
+	"math"
+	"sort"
+)
+
+type Stats struct {
+	Count        int
+	Mean         float64
+	Median       float64
+	WeightedMean float64
+	StdDev       float64
+	Min          float64
+	Max          float64
+}
+
+// newStats summarizes values. Weights must be the same length as values;
+// a zero total weight falls back to the plain mean.
+func newStats(values, weights []float64) Stats {
+	s := Stats{Count: len(values)}
+	if s.Count == 0 {
+		return s
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	s.Min = sorted[0]
+	s.Max = sorted[len(sorted)-1]
+	if mid := len(sorted) / 2; len(sorted)%2 == 0 {
+		s.Median = (sorted[mid-1] + sorted[mid]) / 2
+	} else {
+		s.Median = sorted[mid]
+	}
+
+	var sum, weighted, totalWeight float64
+	for i, v := range values {
+		sum += v
+		weighted += v * weights[i]
+		totalWeight += weights[i]
+	}
+	s.Mean = sum / float64(s.Count)
+	s.WeightedMean = s.Mean
+	if totalWeight > 0 {
+		s.WeightedMean = weighted / totalWeight
+	}
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - s.Mean) * (v - s.Mean)
+	}
+	s.StdDev = math.Sqrt(variance / float64(s.Count))
+	return s
+}
+
+// ZScore is the number of standard deviations v lies from the mean
+func (s Stats) ZScore(v float64) float64 {
+	if s.StdDev == 0 {
+		return 0
+	}
+	return (v - s.Mean) / s.StdDev
+}
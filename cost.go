@@ -0,0 +1,43 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"math"
+	"strings"
+)
+
+// bookmakersPerRegion is how many bookmakers the API counts as one region
+// when bookmakers are requested instead of regions.
+const bookmakersPerRegion = 10
+
+// requestCost is the quota charged for an odds request: the number of
+// markets multiplied by the number of regions.
+func requestCost(regions, markets string, bookmakers *string) int {
+	r := countList(regions)
+	if bookmakers != nil && *bookmakers != "" {
+		r = int(math.Ceil(float64(countList(*bookmakers)) / bookmakersPerRegion))
+	}
+	return max(r, 1) * max(countList(markets), 1)
+}
+
+func countList(s string) int {
+	var n int
+	for _, item := range strings.Split(s, ",") {
+		if strings.TrimSpace(item) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Cost is the quota this request will use
+func (o *OddsParams) Cost() int {
+	return requestCost(o.Region, o.Markets, o.Bookmakers)
+}
+
+// Cost is the quota this request will use
+func (e *EventOddsParams) Cost() int {
+	return requestCost(e.Region, e.Markets, e.Bookmakers)
+}
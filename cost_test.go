@@ -0,0 +1,31 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import "testing"
+
+func TestOddsParams_Cost(t *testing.T) {
+	p := NewOddsParams("api-key", "upcoming")
+	if p.Cost() != 1 {
+		t.Errorf("expected default cost 1, got %d", p.Cost())
+	}
+
+	_ = p.SetRegions(RegionUs, RegionUk)
+	_ = p.SetMarkets(MarketH2H, MarketSpreads, MarketTotals)
+	if p.Cost() != 6 {
+		t.Errorf("expected cost 6, got %d", p.Cost())
+	}
+
+	p.SetBookmakers("b1", "b2", "b3", "b4", "b5", "b6", "b7", "b8", "b9", "b10", "b11")
+	if p.Cost() != 6 {
+		t.Errorf("expected 11 bookmakers to count as 2 regions, got cost %d", p.Cost())
+	}
+}
+
+func TestEventOddsParams_Cost(t *testing.T) {
+	p := &EventOddsParams{Region: "us,us2,eu", Markets: "h2h"}
+	if p.Cost() != 3 {
+		t.Errorf("expected cost 3, got %d", p.Cost())
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package devig removes the bookmaker margin from a set of mutually
+// exclusive prices to estimate fair probabilities.
+package devig
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+type Method string
+
+const (
+	// Multiplicative scales every implied probability by the overround.
+	Multiplicative Method = "multiplicative"
+
+	// Additive subtracts an equal share of the margin from every outcome.
+	Additive Method = "additive"
+
+	// Power raises implied probabilities to the exponent that makes them
+	// sum to one, shifting more of the margin onto longshots.
+	Power Method = "power"
+
+	DefaultMethod = Multiplicative
+)
+
+func (m Method) Valid() bool {
+	switch m {
+	case Multiplicative, Additive, Power:
+		return true
+	}
+	return false
+}
+
+func (m Method) String() string {
+	return string(m)
+}
+
+// Probabilities returns the fair probability of each decimal price. The
+// prices must cover every outcome of the market.
+func Probabilities(decimals []float64, method Method) ([]float64, error) {
+	if len(decimals) < 2 {
+		return nil, errors.New("at least two prices are required")
+	}
+	implied := make([]float64, len(decimals))
+	var total float64
+	for i, d := range decimals {
+		if d <= 1 {
+			return nil, fmt.Errorf("invalid decimal price: %v", d)
+		}
+		implied[i] = 1 / d
+		total += implied[i]
+	}
+
+	switch method {
+	case Multiplicative, "":
+		return multiplicative(implied, total), nil
+	case Additive:
+		return additive(implied, total)
+	case Power:
+		return power(implied), nil
+	}
+	return nil, fmt.Errorf("invalid devig method: %s", method)
+}
+
+func multiplicative(implied []float64, total float64) []float64 {
+	p := make([]float64, len(implied))
+	for i, q := range implied {
+		p[i] = q / total
+	}
+	return p
+}
+
+func additive(implied []float64, total float64) ([]float64, error) {
+	share := (total - 1) / float64(len(implied))
+	p := make([]float64, len(implied))
+	for i, q := range implied {
+		p[i] = q - share
+		if p[i] <= 0 {
+			return nil, fmt.Errorf("additive devig produced a non-positive probability for price %v", 1/q)
+		}
+	}
+	return p, nil
+}
+
+func power(implied []float64) []float64 {
+	sum := func(k float64) float64 {
+		var s float64
+		for _, q := range implied {
+			s += math.Pow(q, k)
+		}
+		return s
+	}
+
+	// The sum falls as k grows, so bisect toward the exponent giving one.
+	lo, hi := 0.0, 1.0
+	for sum(hi) > 1 {
+		lo, hi = hi, hi*2
+	}
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if sum(mid) > 1 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	k := (lo + hi) / 2
+	p := make([]float64, len(implied))
+	for i, q := range implied {
+		p[i] = math.Pow(q, k)
+	}
+	return p
+}
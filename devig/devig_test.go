@@ -0,0 +1,60 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package devig
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProbabilities(t *testing.T) {
+	decimals := []float64{1.5, 2.9, 6.5}
+	for _, method := range []Method{Multiplicative, Additive, Power} {
+		p, err := Probabilities(decimals, method)
+		if err != nil {
+			t.Fatalf("%s: %s", method, err)
+		}
+		var total float64
+		for i, prob := range p {
+			total += prob
+			if prob >= 1/decimals[i] {
+				t.Errorf("%s: expected fair probability below implied for %v, got %v", method, decimals[i], prob)
+			}
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Errorf("%s: expected probabilities to sum to 1, got %v", method, total)
+		}
+	}
+}
+
+func TestProbabilities_Multiplicative(t *testing.T) {
+	p, err := Probabilities([]float64{1.91, 1.91}, Multiplicative)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(p[0]-0.5) > 1e-9 || math.Abs(p[1]-0.5) > 1e-9 {
+		t.Errorf("expected 0.5 each, got %v", p)
+	}
+}
+
+func TestProbabilities_PowerFavorsFavourite(t *testing.T) {
+	decimals := []float64{1.2, 5}
+	m, _ := Probabilities(decimals, Multiplicative)
+	p, _ := Probabilities(decimals, Power)
+	if p[0] <= m[0] {
+		t.Errorf("expected power to give the favourite more probability than multiplicative, got %v and %v", p[0], m[0])
+	}
+}
+
+func TestProbabilities_Invalid(t *testing.T) {
+	if _, err := Probabilities([]float64{1.9}, Multiplicative); err == nil {
+		t.Error("expected an error for a single price")
+	}
+	if _, err := Probabilities([]float64{1.9, 0.5}, Multiplicative); err == nil {
+		t.Error("expected an error for a price below 1")
+	}
+	if _, err := Probabilities([]float64{1.9, 1.9}, "shin"); err == nil {
+		t.Error("expected an error for an unknown method")
+	}
+}
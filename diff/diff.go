@@ -0,0 +1,279 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package diff compares two odds snapshots and reports what changed between
+// them, down to individual outcome prices and points.
+package diff
+
+import (
+	"oddsapi"
+	"sort"
+	"strconv"
+)
+
+type Diff struct {
+	Added   []*oddsapi.Odds
+	Removed []*oddsapi.Odds
+	Changed []*EventDiff
+}
+
+func (d *Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+type EventDiff struct {
+	EventId  string
+	SportKey string
+	HomeTeam string
+	AwayTeam string
+
+	// CommenceTime is set when the start time moved
+	CommenceTime *StringChange
+
+	BookMakersAdded   []string
+	BookMakersRemoved []string
+	MarketsOpened     []*MarketRef
+	MarketsClosed     []*MarketRef
+	Outcomes          []*OutcomeChange
+}
+
+func (e *EventDiff) empty() bool {
+	return e.CommenceTime == nil &&
+		len(e.BookMakersAdded) == 0 && len(e.BookMakersRemoved) == 0 &&
+		len(e.MarketsOpened) == 0 && len(e.MarketsClosed) == 0 &&
+		len(e.Outcomes) == 0
+}
+
+type StringChange struct {
+	Old string
+	New string
+}
+
+type MarketRef struct {
+	BookMaker string
+	Market    string
+}
+
+type OutcomeChangeKind string
+
+const (
+	OutcomeAdded   OutcomeChangeKind = "added"
+	OutcomeRemoved OutcomeChangeKind = "removed"
+	OutcomeMoved   OutcomeChangeKind = "moved"
+)
+
+type OutcomeChange struct {
+	Kind      OutcomeChangeKind
+	BookMaker string
+	Market    string
+	Outcome   string
+
+	// Description is the team of a team_totals outcome
+	Description string
+
+	// Old is nil for added outcomes and New is nil for removed outcomes
+	Old *oddsapi.Outcome
+	New *oddsapi.Outcome
+
+	// PriceChange and PointChange are New minus Old for moved outcomes
+	PriceChange        float64
+	PriceChangePercent float64
+	PointChange        float64
+}
+
+func (o *OutcomeChange) PriceMoved() bool {
+	return o.Kind == OutcomeMoved && o.PriceChange != 0
+}
+
+func (o *OutcomeChange) PointMoved() bool {
+	return o.Kind == OutcomeMoved && o.PointChange != 0
+}
+
+// Compare returns the changes going from prev to curr. Events, bookmakers,
+// markets and outcomes are matched by id, key and name rather than position.
+func Compare(prev, curr []*oddsapi.Odds) *Diff {
+	d := &Diff{}
+	prevEvents := indexEvents(prev)
+	currEvents := indexEvents(curr)
+
+	for _, e := range curr {
+		if e == nil {
+			continue
+		}
+		old, ok := prevEvents[e.Id]
+		if !ok {
+			d.Added = append(d.Added, e)
+			continue
+		}
+		if ed := compareEvent(old, e); !ed.empty() {
+			d.Changed = append(d.Changed, ed)
+		}
+	}
+	for _, e := range prev {
+		if e == nil {
+			continue
+		}
+		if _, ok := currEvents[e.Id]; !ok {
+			d.Removed = append(d.Removed, e)
+		}
+	}
+	return d
+}
+
+func compareEvent(prev, curr *oddsapi.Odds) *EventDiff {
+	ed := &EventDiff{
+		EventId:  curr.Id,
+		SportKey: curr.SportKey,
+		HomeTeam: curr.HomeTeam,
+		AwayTeam: curr.AwayTeam,
+	}
+	if prev.CommenceTime != curr.CommenceTime {
+		ed.CommenceTime = &StringChange{Old: prev.CommenceTime, New: curr.CommenceTime}
+	}
+
+	prevBooks := indexBookMakers(prev.BookMakers)
+	currBooks := indexBookMakers(curr.BookMakers)
+	for _, b := range curr.BookMakers {
+		old, ok := prevBooks[b.Key]
+		if !ok {
+			ed.BookMakersAdded = append(ed.BookMakersAdded, b.Key)
+			continue
+		}
+		compareBookMaker(ed, old, b)
+	}
+	for _, b := range prev.BookMakers {
+		if _, ok := currBooks[b.Key]; !ok {
+			ed.BookMakersRemoved = append(ed.BookMakersRemoved, b.Key)
+		}
+	}
+	return ed
+}
+
+func compareBookMaker(ed *EventDiff, prev, curr *oddsapi.BookMaker) {
+	prevMarkets := indexMarkets(prev.Markets)
+	currMarkets := indexMarkets(curr.Markets)
+	for _, m := range curr.Markets {
+		old, ok := prevMarkets[m.Key]
+		if !ok {
+			ed.MarketsOpened = append(ed.MarketsOpened, &MarketRef{BookMaker: curr.Key, Market: m.Key})
+			continue
+		}
+		ed.Outcomes = append(ed.Outcomes, compareMarket(curr.Key, old, m)...)
+	}
+	for _, m := range prev.Markets {
+		if _, ok := currMarkets[m.Key]; !ok {
+			ed.MarketsClosed = append(ed.MarketsClosed, &MarketRef{BookMaker: curr.Key, Market: m.Key})
+		}
+	}
+}
+
+func compareMarket(bookMaker string, prev, curr *oddsapi.Market) []*OutcomeChange {
+	var result []*OutcomeChange
+	prevOutcomes := indexOutcomes(prev.Outcomes)
+	currOutcomes := indexOutcomes(curr.Outcomes)
+
+	keys := make([]string, 0, len(currOutcomes))
+	for key := range currOutcomes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		o := currOutcomes[key]
+		old, ok := prevOutcomes[key]
+		if !ok {
+			result = append(result, &OutcomeChange{
+				Kind: OutcomeAdded, BookMaker: bookMaker, Market: curr.Key, Outcome: o.Name,
+				Description: o.Description, New: o,
+			})
+			continue
+		}
+		c := &OutcomeChange{
+			Kind:        OutcomeMoved,
+			BookMaker:   bookMaker,
+			Market:      curr.Key,
+			Outcome:     o.Name,
+			Description: o.Description,
+			Old:         old,
+			New:         o,
+			PriceChange: o.Price - old.Price,
+		}
+		if old.Price != 0 {
+			c.PriceChangePercent = c.PriceChange / old.Price * 100
+		}
+		if old.Point != nil && o.Point != nil {
+			c.PointChange = *o.Point - *old.Point
+		}
+		if c.PriceChange != 0 || c.PointChange != 0 {
+			result = append(result, c)
+		}
+	}
+
+	keys = keys[:0]
+	for key := range prevOutcomes {
+		if _, ok := currOutcomes[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		o := prevOutcomes[key]
+		result = append(result, &OutcomeChange{
+			Kind: OutcomeRemoved, BookMaker: bookMaker, Market: curr.Key, Outcome: o.Name,
+			Description: o.Description, Old: o,
+		})
+	}
+	return result
+}
+
+func indexEvents(odds []*oddsapi.Odds) map[string]*oddsapi.Odds {
+	result := make(map[string]*oddsapi.Odds, len(odds))
+	for _, e := range odds {
+		if e != nil {
+			result[e.Id] = e
+		}
+	}
+	return result
+}
+
+func indexBookMakers(bookMakers []*oddsapi.BookMaker) map[string]*oddsapi.BookMaker {
+	result := make(map[string]*oddsapi.BookMaker, len(bookMakers))
+	for _, b := range bookMakers {
+		result[b.Key] = b
+	}
+	return result
+}
+
+func indexMarkets(markets []*oddsapi.Market) map[string]*oddsapi.Market {
+	result := make(map[string]*oddsapi.Market, len(markets))
+	for _, m := range markets {
+		result[m.Key] = m
+	}
+	return result
+}
+
+// indexOutcomes keys outcomes by name and description, which tells apart
+// each team's Over and Under on team_totals. Markets quoting several lines
+// for the same outcome, such as alternate spreads, are keyed by point too so
+// that each line is compared with itself.
+func indexOutcomes(outcomes []*oddsapi.Outcome) map[string]*oddsapi.Outcome {
+	counts := make(map[string]int)
+	for _, o := range outcomes {
+		counts[outcomeName(o)]++
+	}
+	result := make(map[string]*oddsapi.Outcome, len(outcomes))
+	for _, o := range outcomes {
+		key := outcomeName(o)
+		if counts[key] > 1 && o.Point != nil {
+			key += "|" + strconv.FormatFloat(*o.Point, 'f', -1, 64)
+		}
+		result[key] = o
+	}
+	return result
+}
+
+func outcomeName(o *oddsapi.Outcome) string {
+	if o.Description == "" {
+		return o.Name
+	}
+	return o.Name + "|" + o.Description
+}
@@ -0,0 +1,121 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package diff
+
+import (
+	"math"
+	"oddsapi"
+	"testing"
+)
+
+func point(p float64) *float64 {
+	return &p
+}
+
+func snapshot(commence string, homePrice float64, homePoint float64, extraBook bool) []*oddsapi.Odds {
+	e := &oddsapi.Odds{
+		Id:           "event1",
+		CommenceTime: commence,
+		HomeTeam:     "Home",
+		AwayTeam:     "Away",
+		BookMakers: []*oddsapi.BookMaker{{
+			Key: "book1",
+			Markets: []*oddsapi.Market{{
+				Key: "spreads",
+				Outcomes: []*oddsapi.Outcome{
+					{Name: "Home", Price: homePrice, Point: point(homePoint)},
+					{Name: "Away", Price: 1.91, Point: point(-homePoint)},
+				},
+			}},
+		}},
+	}
+	if extraBook {
+		e.BookMakers = append(e.BookMakers, &oddsapi.BookMaker{Key: "book2"})
+	}
+	return []*oddsapi.Odds{e}
+}
+
+func TestCompare_NoChanges(t *testing.T) {
+	d := Compare(snapshot("2024-01-01T00:00:00Z", 1.91, -3.5, false), snapshot("2024-01-01T00:00:00Z", 1.91, -3.5, false))
+	if !d.Empty() {
+		t.Errorf("expected an empty diff, got %+v", d)
+	}
+}
+
+func TestCompare_EventsAddedRemoved(t *testing.T) {
+	prev := snapshot("2024-01-01T00:00:00Z", 1.91, -3.5, false)
+	curr := []*oddsapi.Odds{{Id: "event2"}}
+
+	d := Compare(prev, curr)
+	if len(d.Added) != 1 || d.Added[0].Id != "event2" {
+		t.Errorf("expected event2 added")
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Id != "event1" {
+		t.Errorf("expected event1 removed")
+	}
+}
+
+func TestCompare_EventChanges(t *testing.T) {
+	prev := snapshot("2024-01-01T00:00:00Z", 1.91, -3.5, true)
+	curr := snapshot("2024-01-01T01:00:00Z", 2.0, -4.5, false)
+	curr[0].BookMakers[0].Markets = append(curr[0].BookMakers[0].Markets, &oddsapi.Market{Key: "h2h"})
+
+	d := Compare(prev, curr)
+	if len(d.Changed) != 1 {
+		t.Fatalf("expected 1 changed event, got %d", len(d.Changed))
+	}
+
+	ed := d.Changed[0]
+	if ed.CommenceTime == nil || ed.CommenceTime.New != "2024-01-01T01:00:00Z" {
+		t.Error("expected a commence time change")
+	}
+	if len(ed.BookMakersRemoved) != 1 || ed.BookMakersRemoved[0] != "book2" {
+		t.Errorf("expected book2 removed, got %v", ed.BookMakersRemoved)
+	}
+	if len(ed.MarketsOpened) != 1 || ed.MarketsOpened[0].Market != "h2h" {
+		t.Errorf("expected h2h opened")
+	}
+	if len(ed.Outcomes) != 2 {
+		t.Fatalf("expected 2 outcome moves, got %d", len(ed.Outcomes))
+	}
+
+	home := ed.Outcomes[1]
+	if home.Outcome != "Home" || !home.PriceMoved() || !home.PointMoved() {
+		t.Fatalf("expected home price and point to move, got %+v", home)
+	}
+	if math.Abs(home.PriceChange-0.09) > 1e-9 || home.PointChange != -1 {
+		t.Errorf("expected +0.09 price and -1 point, got %v and %v", home.PriceChange, home.PointChange)
+	}
+
+	away := ed.Outcomes[0]
+	if away.PriceMoved() || away.PointChange != 1 {
+		t.Errorf("expected away point only to move, got %+v", away)
+	}
+}
+
+func TestCompare_TeamTotals(t *testing.T) {
+	totals := func(homeOver, awayOver float64) []*oddsapi.Odds {
+		return []*oddsapi.Odds{{
+			Id: "event1",
+			BookMakers: []*oddsapi.BookMaker{{
+				Key: "book1",
+				Markets: []*oddsapi.Market{{
+					Key: "team_totals",
+					Outcomes: []*oddsapi.Outcome{
+						{Name: "Over", Description: "Home", Price: homeOver, Point: point(110.5)},
+						{Name: "Over", Description: "Away", Price: awayOver, Point: point(110.5)},
+					},
+				}},
+			}},
+		}}
+	}
+
+	d := Compare(totals(1.9, 1.9), totals(1.9, 2.0))
+	if len(d.Changed) != 1 || len(d.Changed[0].Outcomes) != 1 {
+		t.Fatalf("expected 1 outcome move, got %+v", d.Changed)
+	}
+	if c := d.Changed[0].Outcomes[0]; c.Description != "Away" || math.Abs(c.PriceChange-0.1) > 1e-9 {
+		t.Errorf("expected the away over to move by 0.1, got %+v", c)
+	}
+}
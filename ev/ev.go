@@ -0,0 +1,238 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package ev prices every bookmaker outcome against fair probabilities taken
+// from a sharp reference book or the market consensus.
+package ev
+
+import (
+	"fmt"
+	"oddsapi"
+	"oddsapi/devig"
+	"oddsapi/staking"
+	"sort"
+)
+
+type Options struct {
+	// OddsFormat the prices were requested in. Defaults to decimal.
+	OddsFormat oddsapi.OddsFormat
+
+	// Reference is the key of the bookmaker whose de-vigged prices are
+	// treated as fair, e.g. "pinnacle". Leave empty to use the consensus
+	// of every bookmaker quoting the full market.
+	Reference string
+
+	// Method used to remove the margin. Defaults to multiplicative.
+	Method devig.Method
+
+	// MinEV filters out bets at or below this expected value, e.g. 0.02
+	// for 2%. Zero returns every positive EV bet.
+	MinEV float64
+}
+
+type Bet struct {
+	EventId        string
+	SportKey       string
+	HomeTeam       string
+	AwayTeam       string
+	CommenceTime   string
+	Market         string
+	BookMaker      string
+	BookMakerTitle string
+	Outcome        string
+	Point          *float64
+
+	// Price is the offered price in the requested OddsFormat
+	Price        float64
+	DecimalPrice float64
+
+	FairProbability float64
+
+	// FairPrice is the fair line in the requested OddsFormat
+	FairPrice        float64
+	FairDecimalPrice float64
+
+	// EV is the expected return per unit staked
+	EV float64
+
+	// Kelly is the full Kelly fraction of bankroll for this bet alone
+	Kelly float64
+}
+
+// fairLines maps a market line key to the fair probability of each outcome
+type fairLines map[string]map[string]float64
+
+// Find returns every +EV outcome in odds sorted by EV, highest first. The
+// reference bookmaker itself is never returned.
+func Find(odds []*oddsapi.Odds, opts Options) ([]*Bet, error) {
+	if opts.OddsFormat != "" && !opts.OddsFormat.Valid() {
+		return nil, fmt.Errorf("invalid odds format: %s", opts.OddsFormat)
+	}
+	if opts.Method != "" && !opts.Method.Valid() {
+		return nil, fmt.Errorf("invalid devig method: %s", opts.Method)
+	}
+
+	var result []*Bet
+	for _, event := range odds {
+		if event == nil {
+			continue
+		}
+		fair := fairProbabilities(event, opts)
+		if len(fair) == 0 {
+			continue
+		}
+		for _, b := range event.BookMakers {
+			if b.Key == opts.Reference {
+				continue
+			}
+			for _, m := range b.Markets {
+				for _, o := range m.Outcomes {
+					p, ok := fair[event.LineKey(m.Key, o)][o.Name]
+					if !ok {
+						continue
+					}
+					bet, err := newBet(event, b, m, o, p, opts.OddsFormat)
+					if err != nil || bet.EV <= opts.MinEV {
+						continue
+					}
+					result = append(result, bet)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].EV > result[j].EV
+	})
+	return result, nil
+}
+
+func newBet(event *oddsapi.Odds, b *oddsapi.BookMaker, m *oddsapi.Market, o *oddsapi.Outcome, p float64, format oddsapi.OddsFormat) (*Bet, error) {
+	decimal, err := format.ToDecimal(o.Price)
+	if err != nil {
+		return nil, err
+	}
+	fairDecimal := 1 / p
+	fairPrice, err := format.FromDecimal(fairDecimal)
+	if err != nil {
+		return nil, err
+	}
+	kelly, err := staking.Kelly(p, o.Price, format)
+	if err != nil {
+		return nil, err
+	}
+	return &Bet{
+		EventId:          event.Id,
+		SportKey:         event.SportKey,
+		HomeTeam:         event.HomeTeam,
+		AwayTeam:         event.AwayTeam,
+		CommenceTime:     event.CommenceTime,
+		Market:           m.Key,
+		BookMaker:        b.Key,
+		BookMakerTitle:   b.Title,
+		Outcome:          o.Name,
+		Point:            o.Point,
+		Price:            o.Price,
+		DecimalPrice:     decimal,
+		FairProbability:  p,
+		FairPrice:        fairPrice,
+		FairDecimalPrice: fairDecimal,
+		EV:               p*decimal - 1,
+		Kelly:            kelly,
+	}, nil
+}
+
+// fairProbabilities de-vigs the reference bookmaker, or averages the
+// de-vigged lines of every bookmaker when no reference is set. Only books
+// quoting every outcome of a line are de-vigged, since normalizing a subset
+// would inflate its probabilities.
+func fairProbabilities(event *oddsapi.Odds, opts Options) fairLines {
+	full := outcomeSets(event, opts)
+	sums := make(fairLines)
+	counts := make(map[string]map[string]int)
+	for _, b := range event.BookMakers {
+		if opts.Reference != "" && b.Key != opts.Reference {
+			continue
+		}
+		for key, outcomes := range devigBookMaker(event, b, full, opts) {
+			if _, ok := sums[key]; !ok {
+				sums[key] = make(map[string]float64)
+				counts[key] = make(map[string]int)
+			}
+			for name, p := range outcomes {
+				sums[key][name] += p
+				counts[key][name]++
+			}
+		}
+	}
+	for key, outcomes := range sums {
+		for name := range outcomes {
+			outcomes[name] /= float64(counts[key][name])
+		}
+	}
+	return sums
+}
+
+// outcomeSets returns the outcome names quoted on each line by any bookmaker
+func outcomeSets(event *oddsapi.Odds, opts Options) map[string]map[string]bool {
+	sets := make(map[string]map[string]bool)
+	for _, b := range event.BookMakers {
+		for _, m := range b.Markets {
+			for _, o := range m.Outcomes {
+				if _, err := opts.OddsFormat.ToDecimal(o.Price); err != nil {
+					continue
+				}
+				key := event.LineKey(m.Key, o)
+				if _, ok := sets[key]; !ok {
+					sets[key] = make(map[string]bool)
+				}
+				sets[key][o.Name] = true
+			}
+		}
+	}
+	return sets
+}
+
+// complete reports whether names covers every outcome of a line, as the
+// arbitrage scanner requires before comparing a line
+func complete(names []string, full map[string]bool) bool {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !full[name] {
+			return false
+		}
+		seen[name] = true
+	}
+	return len(seen) >= 2 && len(seen) == len(full) && len(names) == len(seen)
+}
+
+func devigBookMaker(event *oddsapi.Odds, b *oddsapi.BookMaker, full map[string]map[string]bool, opts Options) fairLines {
+	result := make(fairLines)
+	for _, m := range b.Markets {
+		names := make(map[string][]string)
+		prices := make(map[string][]float64)
+		for _, o := range m.Outcomes {
+			decimal, err := opts.OddsFormat.ToDecimal(o.Price)
+			if err != nil {
+				continue
+			}
+			key := event.LineKey(m.Key, o)
+			names[key] = append(names[key], o.Name)
+			prices[key] = append(prices[key], decimal)
+		}
+		for key, decimals := range prices {
+			if !complete(names[key], full[key]) {
+				continue
+			}
+			p, err := devig.Probabilities(decimals, opts.Method)
+			if err != nil {
+				continue
+			}
+			result[key] = make(map[string]float64)
+			for i, name := range names[key] {
+				result[key][name] = p[i]
+			}
+		}
+	}
+	return result
+}
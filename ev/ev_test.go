@@ -0,0 +1,118 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package ev
+
+import (
+	"math"
+	"oddsapi"
+	"testing"
+)
+
+func h2h(key string, home, away float64) *oddsapi.BookMaker {
+	return &oddsapi.BookMaker{Key: key, Title: key, Markets: []*oddsapi.Market{{
+		Key: "h2h",
+		Outcomes: []*oddsapi.Outcome{
+			{Name: "Home", Price: home},
+			{Name: "Away", Price: away},
+		},
+	}}}
+}
+
+func event(books ...*oddsapi.BookMaker) []*oddsapi.Odds {
+	return []*oddsapi.Odds{{
+		Id:         "event1",
+		HomeTeam:   "Home",
+		AwayTeam:   "Away",
+		BookMakers: books,
+	}}
+}
+
+func TestFind_Reference(t *testing.T) {
+	odds := event(
+		h2h("pinnacle", 1.95, 1.95),
+		h2h("book1", 2.1, 1.8),
+		h2h("book2", 1.9, 1.9),
+	)
+
+	result, err := Find(odds, Options{Reference: "pinnacle"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 bet, got %d", len(result))
+	}
+
+	b := result[0]
+	if b.BookMaker != "book1" || b.Outcome != "Home" {
+		t.Errorf("expected Home at book1, got %s at %s", b.Outcome, b.BookMaker)
+	}
+	if math.Abs(b.FairProbability-0.5) > 1e-9 || math.Abs(b.FairDecimalPrice-2) > 1e-9 {
+		t.Errorf("expected fair probability 0.5 at 2.0, got %v at %v", b.FairProbability, b.FairDecimalPrice)
+	}
+	if math.Abs(b.EV-0.05) > 1e-9 {
+		t.Errorf("expected EV 0.05, got %v", b.EV)
+	}
+	if math.Abs(b.Kelly-0.05/1.1) > 1e-9 {
+		t.Errorf("expected Kelly %v, got %v", 0.05/1.1, b.Kelly)
+	}
+}
+
+func TestFind_ConsensusAmerican(t *testing.T) {
+	odds := event(
+		h2h("book1", -110, -110),
+		h2h("book2", -110, -110),
+		h2h("book3", 120, -140),
+	)
+
+	result, err := Find(odds, Options{OddsFormat: oddsapi.AmericanOddsFormat})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) == 0 {
+		t.Fatal("expected at least one bet")
+	}
+	if result[0].BookMaker != "book3" || result[0].Outcome != "Home" {
+		t.Errorf("expected Home at book3 to be the best bet, got %s at %s", result[0].Outcome, result[0].BookMaker)
+	}
+	if result[0].FairPrice >= 120 || result[0].FairPrice <= -110 {
+		t.Errorf("expected the fair american line between -110 and +120, got %v", result[0].FairPrice)
+	}
+	for i := 1; i < len(result); i++ {
+		if result[i].EV > result[i-1].EV {
+			t.Error("expected bets sorted by EV")
+		}
+	}
+}
+
+func TestFind_Invalid(t *testing.T) {
+	if _, err := Find(nil, Options{Method: "shin"}); err == nil {
+		t.Error("expected an error for an unknown devig method")
+	}
+}
+
+func TestFind_IncompleteMarket(t *testing.T) {
+	threeWay := func(key string, home, draw, away float64) *oddsapi.BookMaker {
+		return &oddsapi.BookMaker{Key: key, Title: key, Markets: []*oddsapi.Market{{
+			Key: "h2h",
+			Outcomes: []*oddsapi.Outcome{
+				{Name: "Home", Price: home},
+				{Name: "Draw", Price: draw},
+				{Name: "Away", Price: away},
+			},
+		}}}
+	}
+	odds := event(
+		threeWay("book1", 2.5, 3.2, 2.9),
+		threeWay("book2", 2.5, 3.2, 2.9),
+		h2h("book3", 1.6, 1.8),
+	)
+
+	result, err := Find(odds, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 0 {
+		t.Errorf("expected no bets, got %s at %s with EV %v", result[0].Outcome, result[0].BookMaker, result[0].EV)
+	}
+}
@@ -36,7 +36,7 @@ type EventParams struct {
 	// Commence on or before this option. Optional
 	// No effect if sport is upcoming
 	// ISO 8601
-	CommenceTimeTo *string `url:"commentTimeTo,omitempty"`
+	CommenceTimeTo *string `url:"commenceTimeTo,omitempty"`
 }
 
 func (e *EventParams) SetEventIds(eventIds ...string) {
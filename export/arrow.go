@@ -0,0 +1,95 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package export
+
+import (
+	"errors"
+	"github.com/apache/arrow/go/v15/arrow"
+	"github.com/apache/arrow/go/v15/arrow/array"
+	"github.com/apache/arrow/go/v15/arrow/ipc"
+	"github.com/apache/arrow/go/v15/arrow/memory"
+	"io"
+	"oddsapi"
+)
+
+// ArrowSchema has the same columns as ParquetRow, in the same order
+var ArrowSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "fetched_at", Type: arrow.FixedWidthTypes.Timestamp_ms},
+	{Name: "region", Type: arrow.BinaryTypes.String},
+	{Name: "odds_format", Type: arrow.BinaryTypes.String},
+	{Name: "id", Type: arrow.BinaryTypes.String},
+	{Name: "sport_key", Type: arrow.BinaryTypes.String},
+	{Name: "sport_title", Type: arrow.BinaryTypes.String},
+	{Name: "commence_time", Type: arrow.BinaryTypes.String},
+	{Name: "home_team", Type: arrow.BinaryTypes.String},
+	{Name: "away_team", Type: arrow.BinaryTypes.String},
+	{Name: "bookmaker_key", Type: arrow.BinaryTypes.String},
+	{Name: "bookmaker_title", Type: arrow.BinaryTypes.String},
+	{Name: "bookmaker_last_update", Type: arrow.BinaryTypes.String},
+	{Name: "market_key", Type: arrow.BinaryTypes.String},
+	{Name: "market_last_update", Type: arrow.BinaryTypes.String},
+	{Name: "outcome_name", Type: arrow.BinaryTypes.String},
+	{Name: "outcome_price", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	{Name: "outcome_point", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	{Name: "outcome_description", Type: arrow.BinaryTypes.String},
+}, nil)
+
+// ArrowWriter streams rows in the Arrow IPC stream format, one record batch
+// per Write, which pyarrow reads with pyarrow.ipc.open_stream. The stream is
+// only complete once Close returns.
+type ArrowWriter struct {
+	w *ipc.Writer
+	b *array.RecordBuilder
+}
+
+func NewArrowWriter(w io.Writer) *ArrowWriter {
+	mem := memory.NewGoAllocator()
+	return &ArrowWriter{
+		w: ipc.NewWriter(w, ipc.WithSchema(ArrowSchema), ipc.WithAllocator(mem)),
+		b: array.NewRecordBuilder(mem, ArrowSchema),
+	}
+}
+
+func (w *ArrowWriter) Write(s *Snapshot, odds []*oddsapi.Odds) error {
+	if s == nil || s.FetchedAt.IsZero() {
+		return errors.New("snapshot needs a fetch time")
+	}
+	rows := Flatten(odds)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for _, r := range rows {
+		p := newParquetRow(s, r)
+		w.b.Field(0).(*array.TimestampBuilder).Append(arrow.Timestamp(p.FetchedAt.UnixMilli()))
+		for i, v := range []string{
+			p.Region, p.OddsFormat, p.Id, p.SportKey, p.SportTitle, p.CommenceTime,
+			p.HomeTeam, p.AwayTeam, p.BookMakerKey, p.BookMakerTitle, p.BookMakerLastUpdate,
+			p.MarketKey, p.MarketLastUpdate, p.OutcomeName,
+		} {
+			w.b.Field(i + 1).(*array.StringBuilder).Append(v)
+		}
+		appendFloat(w.b.Field(15).(*array.Float64Builder), p.OutcomePrice)
+		appendFloat(w.b.Field(16).(*array.Float64Builder), p.OutcomePoint)
+		w.b.Field(17).(*array.StringBuilder).Append(p.OutcomeDescription)
+	}
+
+	rec := w.b.NewRecord()
+	defer rec.Release()
+	return w.w.Write(rec)
+}
+
+// Close ends the stream, writing the schema if no rows were written
+func (w *ArrowWriter) Close() error {
+	w.b.Release()
+	return w.w.Close()
+}
+
+func appendFloat(b *array.Float64Builder, f *float64) {
+	if f == nil {
+		b.AppendNull()
+		return
+	}
+	b.Append(*f)
+}
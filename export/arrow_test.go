@@ -0,0 +1,86 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package export
+
+import (
+	"bytes"
+	"github.com/apache/arrow/go/v15/arrow"
+	"github.com/apache/arrow/go/v15/arrow/array"
+	"github.com/apache/arrow/go/v15/arrow/ipc"
+	"oddsapi"
+	"testing"
+	"time"
+)
+
+func TestArrowWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewArrowWriter(&buf)
+	snapshot := &Snapshot{
+		FetchedAt:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Region:     oddsapi.RegionUs.String(),
+		OddsFormat: oddsapi.DecimalOddsFormat,
+	}
+	if err := w.Write(snapshot, sample()); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(&Snapshot{}, sample()); err == nil {
+		t.Error("expected an error for a snapshot without a fetch time")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := ipc.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Release()
+	if !r.Schema().Equal(ArrowSchema) {
+		t.Errorf("unexpected schema %s", r.Schema())
+	}
+	if !r.Next() {
+		t.Fatal("expected a record batch")
+	}
+	rec := r.Record()
+	if n := int(rec.NumRows()); n != len(Flatten(sample())) {
+		t.Fatalf("expected %d rows, got %d", len(Flatten(sample())), n)
+	}
+
+	fetchedAt := rec.Column(0).(*array.Timestamp)
+	region := rec.Column(1).(*array.String)
+	market := rec.Column(12).(*array.String)
+	points := rec.Column(16).(*array.Float64)
+	for i := 0; i < int(rec.NumRows()); i++ {
+		if fetchedAt.Value(i) != arrow.Timestamp(snapshot.FetchedAt.UnixMilli()) || region.Value(i) != "us" {
+			t.Errorf("unexpected snapshot columns in row %d", i)
+		}
+	}
+	if market.Value(2) != "spreads" || points.IsNull(2) || points.Value(2) != -3.5 {
+		t.Errorf("unexpected spread row %s %v", market.Value(2), points.Value(2))
+	}
+	if !points.IsNull(0) {
+		t.Errorf("expected no point on h2h, got %v", points.Value(0))
+	}
+	if r.Next() {
+		t.Error("expected a single record batch")
+	}
+	if err = r.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArrowWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewArrowWriter(&buf).Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := ipc.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Release()
+	if r.Next() || !r.Schema().Equal(ArrowSchema) {
+		t.Error("expected an empty stream with the schema")
+	}
+}
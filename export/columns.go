@@ -0,0 +1,182 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package export
+
+import (
+	"fmt"
+	"oddsapi"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type level int
+
+const (
+	levelOdds level = iota
+	levelBookMaker
+	levelMarket
+	levelOutcome
+)
+
+// column is one scalar field of a nested type, named after its csv tag
+// with a prefix for the level it comes from.
+type column struct {
+	name  string
+	level level
+	index int
+}
+
+var (
+	levelTypes = []reflect.Type{
+		reflect.TypeOf(oddsapi.Odds{}),
+		reflect.TypeOf(oddsapi.BookMaker{}),
+		reflect.TypeOf(oddsapi.Market{}),
+		reflect.TypeOf(oddsapi.Outcome{}),
+	}
+	levelPrefixes = []string{"", "bookmaker_", "market_", "outcome_"}
+	allColumns    = buildColumns()
+)
+
+func buildColumns() []*column {
+	var result []*column
+	for l, t := range levelTypes {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := strings.Split(f.Tag.Get("csv"), ",")[0]
+			if tag == "" || tag == "-" || f.Type.Kind() == reflect.Slice {
+				continue
+			}
+			result = append(result, &column{name: levelPrefixes[l] + tag, level: level(l), index: i})
+		}
+	}
+	return result
+}
+
+// Columns returns the name of every available column in default order
+func Columns() []string {
+	names := make([]string, len(allColumns))
+	for i, c := range allColumns {
+		names[i] = c.name
+	}
+	return names
+}
+
+func lookupColumns(names []string) ([]*column, error) {
+	if len(names) == 0 {
+		return allColumns, nil
+	}
+	result := make([]*column, len(names))
+	for i, name := range names {
+		for _, c := range allColumns {
+			if c.name == name {
+				result[i] = c
+				break
+			}
+		}
+		if result[i] == nil {
+			return nil, fmt.Errorf("unknown column: %s", name)
+		}
+	}
+	return result, nil
+}
+
+func (r *Row) level(l level) reflect.Value {
+	var v any
+	switch l {
+	case levelOdds:
+		v = r.Odds
+	case levelBookMaker:
+		v = r.BookMaker
+	case levelMarket:
+		v = r.Market
+	case levelOutcome:
+		v = r.Outcome
+	}
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return reflect.Value{}
+	}
+	return rv.Elem()
+}
+
+func (c *column) format(r *Row) string {
+	s := r.level(c.level)
+	if !s.IsValid() {
+		return ""
+	}
+	f := s.Field(c.index)
+	if f.Kind() == reflect.Pointer {
+		if f.IsNil() {
+			return ""
+		}
+		f = f.Elem()
+	}
+	switch f.Kind() {
+	case reflect.String:
+		return f.String()
+	case reflect.Float64:
+		return strconv.FormatFloat(f.Float(), 'f', -1, 64)
+	case reflect.Bool:
+		return strconv.FormatBool(f.Bool())
+	}
+	return fmt.Sprint(f.Interface())
+}
+
+// parse sets the column's field on r, creating the level if needed. Empty
+// values leave the field at its zero value.
+func (c *column) parse(r *Row, value string) error {
+	if value == "" {
+		return nil
+	}
+	s := r.ensure(c.level).Field(c.index)
+	target := s
+	if s.Kind() == reflect.Pointer {
+		target = reflect.New(s.Type().Elem()).Elem()
+	}
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(value)
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", c.name, err)
+		}
+		target.SetFloat(v)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", c.name, err)
+		}
+		target.SetBool(v)
+	default:
+		return fmt.Errorf("unsupported column type for %s", c.name)
+	}
+	if s.Kind() == reflect.Pointer {
+		s.Set(target.Addr())
+	}
+	return nil
+}
+
+func (r *Row) ensure(l level) reflect.Value {
+	switch l {
+	case levelOdds:
+		if r.Odds == nil {
+			r.Odds = &oddsapi.Odds{}
+		}
+	case levelBookMaker:
+		if r.BookMaker == nil {
+			r.BookMaker = &oddsapi.BookMaker{}
+		}
+	case levelMarket:
+		if r.Market == nil {
+			r.Market = &oddsapi.Market{}
+		}
+	case levelOutcome:
+		if r.Outcome == nil {
+			r.Outcome = &oddsapi.Outcome{}
+		}
+	}
+	return r.level(l)
+}
@@ -0,0 +1,155 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"oddsapi"
+)
+
+type Format string
+
+const (
+	CSV Format = "csv"
+	TSV Format = "tsv"
+)
+
+func (f Format) Valid() bool {
+	switch f {
+	case CSV, TSV:
+		return true
+	}
+	return false
+}
+
+func (f Format) String() string {
+	return string(f)
+}
+
+func (f Format) comma() rune {
+	if f == TSV {
+		return '\t'
+	}
+	return ','
+}
+
+// Writer writes flattened odds with a header row.
+type Writer struct {
+	w             *csv.Writer
+	columns       []*column
+	headerWritten bool
+}
+
+// NewWriter writes the given columns in order, or every column when none
+// are given. See Columns for the available names.
+func NewWriter(w io.Writer, format Format, columns ...string) (*Writer, error) {
+	if !format.Valid() {
+		return nil, fmt.Errorf("invalid export format: %s", format)
+	}
+	cols, err := lookupColumns(columns)
+	if err != nil {
+		return nil, err
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = format.comma()
+	return &Writer{w: cw, columns: cols}, nil
+}
+
+func (w *Writer) Write(odds []*oddsapi.Odds) error {
+	return w.WriteRows(Flatten(odds))
+}
+
+func (w *Writer) WriteRows(rows []*Row) error {
+	if !w.headerWritten {
+		header := make([]string, len(w.columns))
+		for i, c := range w.columns {
+			header[i] = c.name
+		}
+		if err := w.w.Write(header); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	record := make([]string, len(w.columns))
+	for _, r := range rows {
+		for i, c := range w.columns {
+			record[i] = c.format(r)
+		}
+		if err := w.w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// Reader reads rows written by Writer. Columns are matched by the header,
+// so any subset and order is accepted, but rows can only be nested back
+// into odds when the id, bookmaker_key and market_key columns are present.
+type Reader struct {
+	r       *csv.Reader
+	columns []*column
+}
+
+func NewReader(r io.Reader, format Format) (*Reader, error) {
+	if !format.Valid() {
+		return nil, fmt.Errorf("invalid export format: %s", format)
+	}
+	cr := csv.NewReader(r)
+	cr.Comma = format.comma()
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("missing header row")
+	} else if err != nil {
+		return nil, err
+	}
+	cols, err := lookupColumns(header)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{r: cr, columns: cols}, nil
+}
+
+// Read returns the next row or io.EOF
+func (r *Reader) Read() (*Row, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	row := &Row{}
+	for i, c := range r.columns {
+		if err = c.parse(row, record[i]); err != nil {
+			return nil, err
+		}
+	}
+	return row, nil
+}
+
+func (r *Reader) ReadRows() ([]*Row, error) {
+	var rows []*Row
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		} else if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+// ReadAll reads every row and nests them back into odds
+func (r *Reader) ReadAll() ([]*oddsapi.Odds, error) {
+	rows, err := r.ReadRows()
+	if err != nil {
+		return nil, err
+	}
+	return Unflatten(rows), nil
+}
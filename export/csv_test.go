@@ -0,0 +1,111 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"oddsapi"
+	"strings"
+	"testing"
+)
+
+func point(p float64) *float64 {
+	return &p
+}
+
+func sample() []*oddsapi.Odds {
+	return []*oddsapi.Odds{
+		{
+			Id:           "event1",
+			SportKey:     "basketball_nba",
+			SportTitle:   "NBA",
+			CommenceTime: "2024-01-01T00:00:00Z",
+			HomeTeam:     "Home",
+			AwayTeam:     "Away, The",
+			BookMakers: []*oddsapi.BookMaker{{
+				Key:        "book1",
+				Title:      "Book 1",
+				LastUpdate: "2024-01-01T00:00:00Z",
+				Markets: []*oddsapi.Market{
+					{Key: "h2h", LastUpdate: "2024-01-01T00:00:00Z", Outcomes: []*oddsapi.Outcome{
+						{Name: "Home", Price: 1.9},
+						{Name: "Away, The", Price: 1.95},
+					}},
+					{Key: "spreads", LastUpdate: "2024-01-01T00:00:00Z", Outcomes: []*oddsapi.Outcome{
+						{Name: "Home", Price: 1.91, Point: point(-3.5)},
+						{Name: "Away, The", Price: 1.91, Point: point(3.5)},
+					}},
+				},
+			}},
+		},
+		{Id: "event2", SportKey: "basketball_nba", HomeTeam: "A", AwayTeam: "B"},
+	}
+}
+
+func TestColumns(t *testing.T) {
+	columns := Columns()
+	expected := []string{
+		"id", "sport_key", "sport_title", "commence_time", "home_team", "away_team",
+		"bookmaker_key", "bookmaker_title", "bookmaker_last_update",
+		"market_key", "market_last_update",
+		"outcome_name", "outcome_price", "outcome_point", "outcome_description",
+	}
+	if strings.Join(columns, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected columns %v, got %v", expected, columns)
+	}
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	for _, format := range []Format{CSV, TSV} {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = w.Write(sample()); err != nil {
+			t.Fatal(err)
+		}
+		if lines := strings.Count(buf.String(), "\n"); lines != 6 {
+			t.Errorf("%s: expected a header and 5 rows, got %d lines", format, lines)
+		}
+
+		r, err := NewReader(&buf, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		odds, err := r.ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected, _ := json.Marshal(sample())
+		result, _ := json.Marshal(odds)
+		if !bytes.Equal(expected, result) {
+			t.Errorf("%s: expected round trip\n%s\ngot\n%s", format, expected, result)
+		}
+	}
+}
+
+func TestWriter_Columns(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, CSV, "id", "bookmaker_key", "outcome_name", "outcome_point")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Write(sample()[:1]); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "id,bookmaker_key,outcome_name,outcome_point" {
+		t.Errorf("unexpected header: %s", lines[0])
+	}
+	if lines[3] != "event1,book1,Home,-3.5" {
+		t.Errorf("unexpected row: %s", lines[3])
+	}
+
+	if _, err = NewWriter(&buf, CSV, "unknown"); err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+}
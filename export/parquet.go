@@ -0,0 +1,170 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package export
+
+import (
+	"errors"
+	"fmt"
+	"github.com/parquet-go/parquet-go"
+	"oddsapi"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Snapshot describes the request a set of odds was fetched with. It is
+// written on every row because the odds themselves do not carry it.
+type Snapshot struct {
+	FetchedAt  time.Time
+	Region     string
+	OddsFormat oddsapi.OddsFormat
+}
+
+// ParquetRow is the schema of every Parquet file. Columns are only ever
+// added to the end so older files stay readable alongside newer ones.
+type ParquetRow struct {
+	FetchedAt           time.Time `parquet:"fetched_at,timestamp(millisecond)"`
+	Region              string    `parquet:"region"`
+	OddsFormat          string    `parquet:"odds_format"`
+	Id                  string    `parquet:"id"`
+	SportKey            string    `parquet:"sport_key"`
+	SportTitle          string    `parquet:"sport_title"`
+	CommenceTime        string    `parquet:"commence_time"`
+	HomeTeam            string    `parquet:"home_team"`
+	AwayTeam            string    `parquet:"away_team"`
+	BookMakerKey        string    `parquet:"bookmaker_key"`
+	BookMakerTitle      string    `parquet:"bookmaker_title"`
+	BookMakerLastUpdate string    `parquet:"bookmaker_last_update"`
+	MarketKey           string    `parquet:"market_key"`
+	MarketLastUpdate    string    `parquet:"market_last_update"`
+	OutcomeName         string    `parquet:"outcome_name"`
+	OutcomePrice        *float64  `parquet:"outcome_price,optional"`
+	OutcomePoint        *float64  `parquet:"outcome_point,optional"`
+	OutcomeDescription  string    `parquet:"outcome_description"`
+}
+
+func newParquetRow(s *Snapshot, r *Row) ParquetRow {
+	p := ParquetRow{
+		FetchedAt:    s.FetchedAt.UTC(),
+		Region:       s.Region,
+		OddsFormat:   s.OddsFormat.String(),
+		Id:           r.Odds.Id,
+		SportKey:     r.Odds.SportKey,
+		SportTitle:   r.Odds.SportTitle,
+		CommenceTime: r.Odds.CommenceTime,
+		HomeTeam:     r.Odds.HomeTeam,
+		AwayTeam:     r.Odds.AwayTeam,
+	}
+	if b := r.BookMaker; b != nil {
+		p.BookMakerKey = b.Key
+		p.BookMakerTitle = b.Title
+		p.BookMakerLastUpdate = b.LastUpdate
+	}
+	if m := r.Market; m != nil {
+		p.MarketKey = m.Key
+		p.MarketLastUpdate = m.LastUpdate
+	}
+	if o := r.Outcome; o != nil {
+		price := o.Price
+		p.OutcomeName = o.Name
+		p.OutcomePrice = &price
+		p.OutcomePoint = o.Point
+		p.OutcomeDescription = o.Description
+	}
+	return p
+}
+
+type partition struct {
+	file   *os.File
+	writer *parquet.GenericWriter[ParquetRow]
+}
+
+// ParquetWriter streams rows into Parquet files under a directory using
+// hive-style partitions by sport and fetch date, e.g.
+//
+//	dir/sport=basketball_nba/date=2024-01-01/part-<id>.parquet
+//
+// which DuckDB reads with read_parquet('dir/**/*.parquet', hive_partitioning = true).
+// Files are only complete once Close returns.
+type ParquetWriter struct {
+	dir        string
+	id         string
+	partitions map[string]*partition
+}
+
+func NewParquetWriter(dir string) (*ParquetWriter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &ParquetWriter{
+		dir:        dir,
+		id:         fmt.Sprintf("%d", time.Now().UnixNano()),
+		partitions: make(map[string]*partition),
+	}, nil
+}
+
+func (w *ParquetWriter) Write(s *Snapshot, odds []*oddsapi.Odds) error {
+	if s == nil || s.FetchedAt.IsZero() {
+		return errors.New("snapshot needs a fetch time")
+	}
+
+	grouped := make(map[string][]ParquetRow)
+	for _, r := range Flatten(odds) {
+		key := partitionPath(r.Odds.SportKey, s.FetchedAt)
+		grouped[key] = append(grouped[key], newParquetRow(s, r))
+	}
+
+	keys := make([]string, 0, len(grouped))
+	for key := range grouped {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		p, err := w.partition(key)
+		if err != nil {
+			return err
+		}
+		if _, err = p.writer.Write(grouped[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *ParquetWriter) partition(key string) (*partition, error) {
+	if p, ok := w.partitions[key]; ok {
+		return p, nil
+	}
+	dir := filepath.Join(w.dir, key)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(filepath.Join(dir, "part-"+w.id+".parquet"))
+	if err != nil {
+		return nil, err
+	}
+	p := &partition{file: f, writer: parquet.NewGenericWriter[ParquetRow](f)}
+	w.partitions[key] = p
+	return p, nil
+}
+
+// Close finishes every open file
+func (w *ParquetWriter) Close() error {
+	var errs []error
+	for key, p := range w.partitions {
+		errs = append(errs, p.writer.Close(), p.file.Close())
+		delete(w.partitions, key)
+	}
+	return errors.Join(errs...)
+}
+
+func partitionPath(sportKey string, fetchedAt time.Time) string {
+	sport := strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(sportKey)
+	if sport == "" {
+		sport = "unknown"
+	}
+	return filepath.Join("sport="+sport, "date="+fetchedAt.UTC().Format(time.DateOnly))
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package export
+
+import (
+	"github.com/parquet-go/parquet-go"
+	"oddsapi"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParquetWriter(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewParquetWriter(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot := &Snapshot{
+		FetchedAt:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Region:     oddsapi.RegionUs.String(),
+		OddsFormat: oddsapi.DecimalOddsFormat,
+	}
+	if err = w.Write(snapshot, sample()); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Write(&Snapshot{}, sample()); err == nil {
+		t.Error("expected an error for a snapshot without a fetch time")
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "sport=basketball_nba", "date=2024-01-01", "*.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %v", files)
+	}
+	rows, err := parquet.ReadFile[ParquetRow](files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(Flatten(sample())) {
+		t.Fatalf("expected %d rows, got %d", len(Flatten(sample())), len(rows))
+	}
+
+	for _, r := range rows {
+		if !r.FetchedAt.Equal(snapshot.FetchedAt) || r.Region != "us" || r.OddsFormat != "decimal" {
+			t.Errorf("unexpected snapshot columns %+v", r)
+		}
+	}
+	spread := rows[2]
+	if spread.MarketKey != "spreads" || spread.OutcomePoint == nil || *spread.OutcomePoint != -3.5 {
+		t.Errorf("unexpected spread row %+v", spread)
+	}
+	if rows[0].OutcomePoint != nil {
+		t.Errorf("expected no point on h2h, got %v", *rows[0].OutcomePoint)
+	}
+}
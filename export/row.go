@@ -0,0 +1,97 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package export flattens odds into one row per event, bookmaker, market and
+// outcome for tabular formats.
+package export
+
+import (
+	"oddsapi"
+)
+
+// Row is one outcome with the event, bookmaker and market it belongs to.
+// Events without bookmakers, or bookmakers without markets, produce a row
+// with the missing levels nil so that they are not lost.
+type Row struct {
+	Odds      *oddsapi.Odds
+	BookMaker *oddsapi.BookMaker
+	Market    *oddsapi.Market
+	Outcome   *oddsapi.Outcome
+}
+
+func Flatten(odds []*oddsapi.Odds) []*Row {
+	var rows []*Row
+	for _, e := range odds {
+		if e == nil {
+			continue
+		}
+		if len(e.BookMakers) == 0 {
+			rows = append(rows, &Row{Odds: e})
+		}
+		for _, b := range e.BookMakers {
+			if len(b.Markets) == 0 {
+				rows = append(rows, &Row{Odds: e, BookMaker: b})
+			}
+			for _, m := range b.Markets {
+				if len(m.Outcomes) == 0 {
+					rows = append(rows, &Row{Odds: e, BookMaker: b, Market: m})
+				}
+				for _, o := range m.Outcomes {
+					rows = append(rows, &Row{Odds: e, BookMaker: b, Market: m, Outcome: o})
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// Unflatten rebuilds nested odds from rows, merging rows that share an
+// event id, bookmaker key and market key. Order of first appearance is kept.
+func Unflatten(rows []*Row) []*oddsapi.Odds {
+	var result []*oddsapi.Odds
+	events := make(map[string]*oddsapi.Odds)
+	bookMakers := make(map[string]*oddsapi.BookMaker)
+	markets := make(map[string]*oddsapi.Market)
+	for _, r := range rows {
+		if r.Odds == nil {
+			continue
+		}
+		e, ok := events[r.Odds.Id]
+		if !ok {
+			e = &oddsapi.Odds{}
+			*e = *r.Odds
+			e.BookMakers = nil
+			events[e.Id] = e
+			result = append(result, e)
+		}
+		if r.BookMaker == nil {
+			continue
+		}
+		bKey := e.Id + "|" + r.BookMaker.Key
+		b, ok := bookMakers[bKey]
+		if !ok {
+			b = &oddsapi.BookMaker{}
+			*b = *r.BookMaker
+			b.Markets = nil
+			bookMakers[bKey] = b
+			e.BookMakers = append(e.BookMakers, b)
+		}
+		if r.Market == nil {
+			continue
+		}
+		mKey := bKey + "|" + r.Market.Key
+		m, ok := markets[mKey]
+		if !ok {
+			m = &oddsapi.Market{}
+			*m = *r.Market
+			m.Outcomes = nil
+			markets[mKey] = m
+			b.Markets = append(b.Markets, m)
+		}
+		if r.Outcome != nil {
+			o := *r.Outcome
+			m.Outcomes = append(m.Outcomes, &o)
+		}
+	}
+	return result
+}
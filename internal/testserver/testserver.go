@@ -0,0 +1,66 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package testserver fakes the Odds API upstream for tests.
+package testserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	Sports = `[{"key":"basketball_nba","active":true}]`
+	Odds   = `[{"id":"event1","sport_key":"basketball_nba","home_team":"Home","away_team":"Away","bookmakers":[{"key":"book1","markets":[]}]}]`
+)
+
+// Server answers the sports endpoint with Sports, event odds with the single
+// event in Odds and every other path with Odds. Each response reports 100
+// requests remaining, 5 used and a cost of 1.
+type Server struct {
+	*httptest.Server
+
+	// Hits counts the requests received, including rejected ones
+	Hits atomic.Int32
+
+	// ApiKey rejects requests with any other key when set
+	ApiKey string
+
+	// Release holds every request until it is closed when set
+	Release chan struct{}
+}
+
+// New starts a Server that is closed when the test finishes
+func New(t testing.TB) *Server {
+	s := &Server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.Hits.Add(1)
+	if s.ApiKey != "" && r.URL.Query().Get("apiKey") != s.ApiKey {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"bad key"}`))
+		return
+	}
+	if s.Release != nil {
+		<-s.Release
+	}
+
+	w.Header().Set("X-Requests-Remaining", "100")
+	w.Header().Set("X-Requests-Used", "5")
+	w.Header().Set("X-Requests-Last", "1")
+	switch {
+	case r.URL.Path == "/v4/sports":
+		_, _ = w.Write([]byte(Sports))
+	case strings.Contains(r.URL.Path, "/events/") && strings.HasSuffix(r.URL.Path, "/odds"):
+		_, _ = w.Write([]byte(Odds[1 : len(Odds)-1]))
+	default:
+		_, _ = w.Write([]byte(Odds))
+	}
+}
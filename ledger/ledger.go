@@ -0,0 +1,267 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package ledger records placed bets and reports closing line value, ROI and
+// win rate once closing lines and scores are known.
+package ledger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"oddsapi"
+	"oddsapi/closing"
+	"oddsapi/settlement"
+	"sync"
+	"time"
+)
+
+type Bet struct {
+	Id string `json:"id"`
+
+	// Event the bet was placed on, as returned by the events endpoint
+	Event *oddsapi.Event `json:"event"`
+
+	BookMaker string   `json:"bookmaker"`
+	Market    string   `json:"market"`
+	Outcome   string   `json:"outcome"`
+	Point     *float64 `json:"point,omitempty"`
+
+	// Team is the outcome description on team_totals bets
+	Team string `json:"team,omitempty"`
+
+	// ThreeWay is set on h2h bets whose market quoted a Draw
+	ThreeWay bool `json:"three_way,omitempty"`
+
+	// Price in the ledger's OddsFormat
+	Price    float64   `json:"price"`
+	Stake    float64   `json:"stake"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+func (b *Bet) validate(format oddsapi.OddsFormat) error {
+	if b.Id == "" {
+		return errors.New("bet id is blank")
+	}
+	if b.Event == nil || b.Event.Id == "" {
+		return fmt.Errorf("bet %s has no event", b.Id)
+	}
+	if b.BookMaker == "" || b.Market == "" || b.Outcome == "" {
+		return fmt.Errorf("bet %s needs a bookmaker, market and outcome", b.Id)
+	}
+	if b.Stake <= 0 {
+		return fmt.Errorf("bet %s has an invalid stake: %v", b.Id, b.Stake)
+	}
+	_, err := format.ToDecimal(b.Price)
+	return err
+}
+
+func (b *Bet) selection() *settlement.Selection {
+	return &settlement.Selection{
+		EventId:  b.Event.Id,
+		Market:   b.Market,
+		Outcome:  b.Outcome,
+		Team:     b.Team,
+		Point:    b.Point,
+		ThreeWay: b.ThreeWay,
+	}
+}
+
+type Ledger struct {
+	OddsFormat oddsapi.OddsFormat
+
+	// ClosingBookMaker is the bookmaker whose closing price every bet is
+	// compared against, e.g. "pinnacle". When empty each bet is compared
+	// against the closing price at the bookmaker it was placed with.
+	ClosingBookMaker string
+
+	closing closing.Store
+
+	mu     sync.Mutex
+	bets   []*Bet
+	ids    map[string]bool
+	scores map[string]*oddsapi.Score
+}
+
+// New creates an empty ledger. The closing store may be nil, in which case
+// closing line value is not reported.
+func New(format oddsapi.OddsFormat, store closing.Store) *Ledger {
+	return &Ledger{
+		OddsFormat: format,
+		closing:    store,
+		ids:        make(map[string]bool),
+		scores:     make(map[string]*oddsapi.Score),
+	}
+}
+
+func (l *Ledger) Record(bet *Bet) error {
+	if err := bet.validate(l.OddsFormat); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.ids[bet.Id] {
+		return fmt.Errorf("bet %s is already recorded", bet.Id)
+	}
+	l.ids[bet.Id] = true
+	l.bets = append(l.bets, bet)
+	return nil
+}
+
+func (l *Ledger) Bets() []*Bet {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]*Bet(nil), l.bets...)
+}
+
+// AddScores stores results from the scores endpoint for settlement
+func (l *Ledger) AddScores(scores ...*oddsapi.Score) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, s := range scores {
+		l.scores[s.Id] = s
+	}
+}
+
+// Save writes every bet as a line of JSON
+func (l *Ledger) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, b := range l.Bets() {
+		if err := enc.Encode(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load records every bet from lines of JSON written by Save
+func (l *Ledger) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var b Bet
+		if err := json.Unmarshal(scanner.Bytes(), &b); err != nil {
+			return err
+		}
+		if err := l.Record(&b); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// Entry is a bet joined to its result and closing line.
+type Entry struct {
+	Bet    *Bet
+	Result settlement.Result
+
+	// Profit is zero until the bet is settled
+	Profit float64
+
+	// ClosingPrice and CLV are nil when no matching closing line exists
+	ClosingPrice *float64
+	CLV          *float64
+
+	// Err is why the bet could not be graded. The entry stays pending.
+	Err error
+}
+
+func (e *Entry) Settled() bool {
+	return e.Result != settlement.Pending
+}
+
+func (l *Ledger) Entries() ([]*Entry, error) {
+	l.mu.Lock()
+	bets := append([]*Bet(nil), l.bets...)
+	scores := make(map[string]*oddsapi.Score, len(l.scores))
+	for id, s := range l.scores {
+		scores[id] = s
+	}
+	l.mu.Unlock()
+
+	result := make([]*Entry, len(bets))
+	for i, b := range bets {
+		decimal, err := l.OddsFormat.ToDecimal(b.Price)
+		if err != nil {
+			return nil, err
+		}
+		e := &Entry{Bet: b}
+		e.Result, err = settlement.Grade(b.selection(), scores[b.Event.Id])
+		if err != nil {
+			e.Err = fmt.Errorf("error settling bet %s: %w", b.Id, err)
+		}
+		e.Profit = e.Result.Profit(b.Stake, decimal)
+		if err = l.closingLineValue(e, decimal); err != nil {
+			return nil, err
+		}
+		result[i] = e
+	}
+	return result, nil
+}
+
+// closingLineValue compares the bet price with the closing price for the
+// same outcome and point: the percentage by which the bet beat the close.
+func (l *Ledger) closingLineValue(e *Entry, decimal float64) error {
+	if l.closing == nil {
+		return nil
+	}
+	line, err := l.closing.ClosingLine(e.Bet.Event.Id)
+	if errors.Is(err, closing.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if line.Odds == nil {
+		return nil
+	}
+
+	bookMaker := l.ClosingBookMaker
+	if bookMaker == "" {
+		bookMaker = e.Bet.BookMaker
+	}
+	o := findOutcome(line.Odds, bookMaker, e.Bet)
+	if o == nil {
+		return nil
+	}
+	closingDecimal, err := l.OddsFormat.ToDecimal(o.Price)
+	if err != nil {
+		return nil
+	}
+	clv := decimal/closingDecimal - 1
+	e.ClosingPrice = &o.Price
+	e.CLV = &clv
+	return nil
+}
+
+// findOutcome returns the bookmaker's outcome for the bet's selection. On
+// team_totals the team tells apart each team's Over and Under.
+func findOutcome(odds *oddsapi.Odds, bookMaker string, bet *Bet) *oddsapi.Outcome {
+	point := bet.Point
+	for _, b := range odds.BookMakers {
+		if b.Key != bookMaker {
+			continue
+		}
+		for _, m := range b.Markets {
+			if m.Key != bet.Market {
+				continue
+			}
+			for _, o := range m.Outcomes {
+				if o.Name != bet.Outcome || (o.Point == nil) != (point == nil) {
+					continue
+				}
+				if bet.Team != "" && o.Description != bet.Team {
+					continue
+				}
+				if point == nil || *o.Point == *point {
+					return o
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package ledger
+
+import (
+	"bytes"
+	"math"
+	"oddsapi"
+	"oddsapi/closing"
+	"oddsapi/settlement"
+	"testing"
+)
+
+func point(p float64) *float64 {
+	return &p
+}
+
+var nba = &oddsapi.Event{Id: "nba1", SportKey: "basketball_nba", HomeTeam: "Home", AwayTeam: "Away"}
+
+func closingStore(t *testing.T) closing.Store {
+	store, err := closing.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.SaveClosingLine(&closing.Line{EventId: "nba1", Odds: &oddsapi.Odds{
+		Id: "nba1",
+		BookMakers: []*oddsapi.BookMaker{{
+			Key: "book1",
+			Markets: []*oddsapi.Market{
+				{Key: "h2h", Outcomes: []*oddsapi.Outcome{{Name: "Home", Price: 1.8}, {Name: "Away", Price: 2.1}}},
+				{Key: "spreads", Outcomes: []*oddsapi.Outcome{
+					{Name: "Home", Price: 1.91, Point: point(-3.5)},
+					{Name: "Away", Price: 1.91, Point: point(3.5)},
+				}},
+				{Key: "team_totals", Outcomes: []*oddsapi.Outcome{
+					{Name: "Over", Description: "Home", Price: 1.8, Point: point(110.5)},
+					{Name: "Over", Description: "Away", Price: 1.9, Point: point(110.5)},
+				}},
+			},
+		}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestLedger_Report(t *testing.T) {
+	l := New(oddsapi.DecimalOddsFormat, closingStore(t))
+	bets := []*Bet{
+		{Id: "1", Event: nba, BookMaker: "book1", Market: "h2h", Outcome: "Home", Price: 2.0, Stake: 10},
+		{Id: "2", Event: nba, BookMaker: "book1", Market: "spreads", Outcome: "Away", Point: point(3.5), Price: 1.95, Stake: 10},
+		{Id: "3", Event: nba, BookMaker: "book2", Market: "totals", Outcome: "Over", Point: point(205.5), Price: 1.9, Stake: 10},
+		{Id: "4", Event: &oddsapi.Event{Id: "nfl1", SportKey: "americanfootball_nfl"}, BookMaker: "book1", Market: "h2h", Outcome: "Home", Price: 1.5, Stake: 10},
+	}
+	for _, b := range bets {
+		if err := l.Record(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.AddScores(&oddsapi.Score{
+		Id:        "nba1",
+		Completed: true,
+		HomeTeam:  "Home",
+		AwayTeam:  "Away",
+		Scores:    []*oddsapi.TeamScore{{Name: "Home", Score: "108"}, {Name: "Away", Score: "102"}},
+	})
+
+	r, err := l.Report()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total := r.Total
+	if total.Bets != 4 || total.Settled != 3 || total.Wins != 2 || total.Losses != 1 {
+		t.Errorf("expected 4 bets with 2 wins and 1 loss settled, got %+v", total)
+	}
+	expectedProfit := 10.0 - 10 + 10*0.9
+	if math.Abs(total.Profit-expectedProfit) > 1e-9 || math.Abs(total.ROI-expectedProfit/30) > 1e-9 {
+		t.Errorf("expected profit %v, got %v", expectedProfit, total.Profit)
+	}
+
+	expectedCLV := ((2.0/1.8 - 1) + (1.95/1.91 - 1)) / 2
+	if total.CLVBets != 2 || math.Abs(total.CLV-expectedCLV) > 1e-9 {
+		t.Errorf("expected CLV %v over 2 bets, got %v over %d", expectedCLV, total.CLV, total.CLVBets)
+	}
+
+	if len(r.BySport) != 2 || r.BySport[1].Key != "basketball_nba" || r.BySport[1].Settled != 3 {
+		t.Errorf("expected the nba group to hold the 3 settled bets")
+	}
+	if len(r.ByBookMaker) != 2 || r.ByBookMaker[1].Key != "book2" || r.ByBookMaker[1].WinRate != 1 {
+		t.Errorf("expected book2 to win its only bet")
+	}
+}
+
+func TestLedger_SaveLoad(t *testing.T) {
+	l := New(oddsapi.AmericanOddsFormat, nil)
+	err := l.Record(&Bet{Id: "1", Event: nba, BookMaker: "book1", Market: "h2h", Outcome: "Home", Price: -110, Stake: 11})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err = l.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded := New(oddsapi.AmericanOddsFormat, nil)
+	if err = loaded.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Bets()) != 1 || loaded.Bets()[0].Event.HomeTeam != "Home" {
+		t.Errorf("expected the bet to round trip, got %+v", loaded.Bets())
+	}
+}
+
+func TestLedger_RecordInvalid(t *testing.T) {
+	l := New(oddsapi.AmericanOddsFormat, nil)
+	if err := l.Record(&Bet{Id: "1", Event: nba, BookMaker: "b", Market: "h2h", Outcome: "Home", Price: 50, Stake: 1}); err == nil {
+		t.Error("expected an error for an invalid american price")
+	}
+	b := &Bet{Id: "1", Event: nba, BookMaker: "b", Market: "h2h", Outcome: "Home", Price: 150, Stake: 1}
+	if err := l.Record(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Record(b); err == nil {
+		t.Error("expected an error recording the same bet twice")
+	}
+}
+
+func TestLedger_EntriesGradeError(t *testing.T) {
+	l := New(oddsapi.DecimalOddsFormat, nil)
+	bets := []*Bet{
+		{Id: "1", Event: nba, BookMaker: "book1", Market: "h2h", Outcome: "Home", Price: 2.0, Stake: 10},
+		{Id: "2", Event: nba, BookMaker: "book1", Market: "player_points", Outcome: "Over", Price: 1.9, Stake: 10},
+	}
+	for _, b := range bets {
+		if err := l.Record(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.AddScores(&oddsapi.Score{
+		Id:        "nba1",
+		Completed: true,
+		HomeTeam:  "Home",
+		AwayTeam:  "Away",
+		Scores:    []*oddsapi.TeamScore{{Name: "Home", Score: "108"}, {Name: "Away", Score: "102"}},
+	})
+
+	entries, err := l.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries[0].Err != nil || entries[0].Result != settlement.Win {
+		t.Errorf("expected the h2h bet to win, got %s (%v)", entries[0].Result, entries[0].Err)
+	}
+	if entries[1].Err == nil || entries[1].Settled() {
+		t.Errorf("expected the ungradable bet to stay pending with an error, got %s", entries[1].Result)
+	}
+}
+
+func TestLedger_TeamTotalsClosingLine(t *testing.T) {
+	l := New(oddsapi.DecimalOddsFormat, closingStore(t))
+	bet := &Bet{Id: "1", Event: nba, BookMaker: "book1", Market: "team_totals", Outcome: "Over", Team: "Away", Point: point(110.5), Price: 1.95, Stake: 10}
+	if err := l.Record(bet); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := l.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := entries[0].ClosingPrice; p == nil || *p != 1.9 {
+		t.Errorf("expected the closing price of the away team's over, got %v", p)
+	}
+}
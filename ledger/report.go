@@ -0,0 +1,126 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package ledger
+
+import (
+	"oddsapi/settlement"
+	"sort"
+)
+
+type Summary struct {
+	Key string
+
+	Bets       int
+	Settled    int
+	Wins       int
+	Losses     int
+	Pushes     int
+	HalfWins   int
+	HalfLosses int
+
+	// Staked and Profit only count settled bets
+	Staked float64
+	Profit float64
+	ROI    float64
+
+	// WinRate excludes pushes and counts half results as half a bet
+	WinRate float64
+
+	// CLV is the mean closing line value over the CLVBets bets that have
+	// a matching closing line
+	CLV     float64
+	CLVBets int
+}
+
+func (s *Summary) add(e *Entry) {
+	s.Bets++
+	if e.CLV != nil {
+		s.CLV += *e.CLV
+		s.CLVBets++
+	}
+	if !e.Settled() {
+		return
+	}
+	s.Settled++
+	s.Staked += e.Bet.Stake
+	s.Profit += e.Profit
+	switch e.Result {
+	case settlement.Win:
+		s.Wins++
+	case settlement.Loss:
+		s.Losses++
+	case settlement.Push:
+		s.Pushes++
+	case settlement.HalfWin:
+		s.HalfWins++
+	case settlement.HalfLoss:
+		s.HalfLosses++
+	}
+}
+
+func (s *Summary) finish() {
+	if s.Staked > 0 {
+		s.ROI = s.Profit / s.Staked
+	}
+	won := float64(s.Wins) + float64(s.HalfWins)/2
+	decided := float64(s.Wins+s.Losses) + float64(s.HalfWins+s.HalfLosses)/2
+	if decided > 0 {
+		s.WinRate = won / decided
+	}
+	if s.CLVBets > 0 {
+		s.CLV /= float64(s.CLVBets)
+	}
+}
+
+type Report struct {
+	Total       *Summary
+	BySport     []*Summary
+	ByMarket    []*Summary
+	ByBookMaker []*Summary
+}
+
+func (l *Ledger) Report() (*Report, error) {
+	entries, err := l.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	total := &Summary{Key: "total"}
+	sports := make(map[string]*Summary)
+	markets := make(map[string]*Summary)
+	bookMakers := make(map[string]*Summary)
+	for _, e := range entries {
+		total.add(e)
+		group(sports, e.Bet.Event.SportKey).add(e)
+		group(markets, e.Bet.Market).add(e)
+		group(bookMakers, e.Bet.BookMaker).add(e)
+	}
+	total.finish()
+
+	return &Report{
+		Total:       total,
+		BySport:     sorted(sports),
+		ByMarket:    sorted(markets),
+		ByBookMaker: sorted(bookMakers),
+	}, nil
+}
+
+func group(groups map[string]*Summary, key string) *Summary {
+	s, ok := groups[key]
+	if !ok {
+		s = &Summary{Key: key}
+		groups[key] = s
+	}
+	return s
+}
+
+func sorted(groups map[string]*Summary) []*Summary {
+	result := make([]*Summary, 0, len(groups))
+	for _, s := range groups {
+		s.finish()
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
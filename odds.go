@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -15,6 +16,10 @@ type Outcome struct {
 	Name  string   `json:"name" csv:"name"`
 	Price float64  `json:"price" csv:"price"`
 	Point *float64 `json:"point,omitempty" csv:"point,omitempty"`
+
+	// Description qualifies the outcome on some markets, such as the team
+	// of a team_totals over or under
+	Description string `json:"description,omitempty" csv:"description,omitempty"`
 }
 
 type Market struct {
@@ -23,6 +28,10 @@ type Market struct {
 	Outcomes   []*Outcome `json:"outcomes" csv:"outcomes"`
 }
 
+func (m *Market) LastUpdateTime() (time.Time, error) {
+	return time.Parse(time.RFC3339, m.LastUpdate)
+}
+
 type BookMaker struct {
 	Key        string    `json:"key" csv:"key"`
 	Title      string    `json:"title" csv:"title"`
@@ -40,6 +49,30 @@ type Odds struct {
 	BookMakers   []*BookMaker `json:"bookmakers" csv:"bookmakers"`
 }
 
+// LinePoint returns the outcome's point as seen from the home team. Spreads
+// quote opposite points for each team, so normalizing lets "home -3.5" and
+// "away +3.5" compare as the same line. Other markets are returned as is.
+func (o *Odds) LinePoint(marketKey string, outcome *Outcome) *float64 {
+	if outcome.Point == nil {
+		return nil
+	}
+	p := *outcome.Point
+	if marketKey == MarketSpreads.String() && outcome.Name == o.AwayTeam {
+		p = -p
+	}
+	return &p
+}
+
+// LineKey identifies the line an outcome is quoted on, the market and its
+// LinePoint, so prices on the same line can be grouped across bookmakers
+func (o *Odds) LineKey(marketKey string, outcome *Outcome) string {
+	point := o.LinePoint(marketKey, outcome)
+	if point == nil {
+		return marketKey
+	}
+	return marketKey + "|" + strconv.FormatFloat(*point, 'f', -1, 64)
+}
+
 type OddsParams struct {
 	SportKey string `url:"-"`
 	ApiToken string `url:"apiKey"`
@@ -67,7 +100,7 @@ type OddsParams struct {
 	// Commence on or before this option. Optional
 	// No effect if sport is upcoming
 	// ISO 8601
-	CommenceTimeTo *string `url:"commentTimeTo,omitempty"`
+	CommenceTimeTo *string `url:"commenceTimeTo,omitempty"`
 }
 
 func NewOddsParams(apiKey, sportKey string) *OddsParams {
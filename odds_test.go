@@ -177,3 +177,20 @@ func TestOddsParams_SetCommenceTimeTo(t *testing.T) {
 		t.Errorf("expected CommenceTimeTo to be '%s', got '%s'", expected, *p.CommenceTimeTo)
 	}
 }
+
+func TestOdds_LineKey(t *testing.T) {
+	point := func(f float64) *float64 { return &f }
+	event := &Odds{HomeTeam: "Home", AwayTeam: "Away"}
+
+	home := event.LineKey("spreads", &Outcome{Name: "Home", Point: point(-3.5)})
+	away := event.LineKey("spreads", &Outcome{Name: "Away", Point: point(3.5)})
+	if home != "spreads|-3.5" || away != home {
+		t.Errorf("expected both sides of a spread on one line, got %s and %s", home, away)
+	}
+	if k := event.LineKey("totals", &Outcome{Name: "Under", Point: point(220)}); k != "totals|220" {
+		t.Errorf("unexpected totals key %s", k)
+	}
+	if k := event.LineKey("h2h", &Outcome{Name: "Home"}); k != "h2h" {
+		t.Errorf("expected the market alone without a point, got %s", k)
+	}
+}
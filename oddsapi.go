@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-retryablehttp"
+	"golang.org/x/sync/singleflight"
 	"golang.org/x/time/rate"
 	"io"
 	"net/http"
@@ -44,11 +45,19 @@ type Client struct {
 	maxPercentOfLimit float64
 	limiterBurst      float64
 	configureOnce     sync.Once
+	mu                sync.RWMutex
+	quota             Quota
+	cache             *responseCache
+	group             singleflight.Group
+
+	// joined, when set, is called once a caller waits on a shared request
+	joined func()
 
 	SportsService    *SportsService
 	OddsService      *OddsService
 	EventService     *EventService
 	EventOddsService *EventOddsService
+	ScoresService    *ScoresService
 }
 
 func NewClient(apiToken string, rateLimitPerSec int, options ...ClientOption) (*Client, error) {
@@ -92,6 +101,7 @@ func NewClient(apiToken string, rateLimitPerSec int, options ...ClientOption) (*
 	c.OddsService = NewOddsService(c)
 	c.EventService = NewEventService(c)
 	c.EventOddsService = NewEventOddsService(c)
+	c.ScoresService = NewScoresService(c)
 
 	err = c.applyOptions(options...)
 	if err != nil {
@@ -134,15 +144,114 @@ func (c *Client) configureRateLimiter(ctx context.Context) {
 	if int(rl*c.limiterBurst) > 1 {
 		burst = int(rl * c.limiterBurst)
 	}
-	c.limiter = rate.NewLimiter(limit, burst)
+	limiter := rate.NewLimiter(limit, burst)
+	c.mu.Lock()
+	c.limiter = limiter
+	c.mu.Unlock()
+
+	_ = limiter.Wait(ctx)
+}
+
+// Wait blocks until the rate limiter allows another request. The limiter is
+// configured after the first response, so requests before then do not wait.
+func (c *Client) Wait(ctx context.Context) error {
+	c.mu.RLock()
+	limiter := c.limiter
+	c.mu.RUnlock()
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Wait(ctx)
+}
 
-	_ = c.limiter.Wait(ctx)
+// Quota returns the most recent usage reported by the API
+func (c *Client) Quota() Quota {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.quota
 }
 
+// Do sends the request and decodes the body into data. Identical concurrent
+// requests share one upstream call and, when a cache is set, responses of
+// cached endpoints are reused within their TTL. The body is decoded afresh
+// for every caller either way.
 func (c *Client) Do(req *retryablehttp.Request, data interface{}) (*Response, error) {
+	response, body, err := c.fetch(req)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, data)
+	if err != nil {
+		return response, err
+	}
+
+	return response, err
+}
+
+type fetchResult struct {
+	response *Response
+	body     []byte
+}
+
+// fetch shares one upstream request between identical concurrent calls,
+// keyed like the cache. Each caller decodes the body itself, so none of them
+// see another's changes to the data. The shared request ignores cancellation
+// of the caller that started it, and each caller stops waiting when its own
+// context is done.
+func (c *Client) fetch(req *retryablehttp.Request) (*Response, []byte, error) {
+	ctx := req.Context()
+	detached := req.WithContext(context.WithoutCancel(ctx))
+	ch := c.group.DoChan(CanonicalKey(req.URL), func() (any, error) {
+		response, body, err := c.fetchCached(detached)
+		return &fetchResult{response: response, body: body}, err
+	})
+	if c.joined != nil {
+		c.joined()
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case r := <-ch:
+		result := r.Val.(*fetchResult)
+		if r.Shared {
+			return result.response.clone(), result.body, r.Err
+		}
+		return result.response, result.body, r.Err
+	}
+}
+
+func (c *Client) fetchCached(req *retryablehttp.Request) (*Response, []byte, error) {
+	if c.cache == nil {
+		return c.send(req)
+	}
+	key, ttl, ok := c.cache.key(req.URL)
+	if !ok {
+		return c.send(req)
+	}
+	if cached, hit := c.cache.get(req.Context(), key, ttl); hit {
+		return cachedResponse(cached, req.Request), cached.Body, nil
+	}
+
+	response, body, err := c.send(req)
+	if err == nil && response.StatusCode == http.StatusOK {
+		cached := &CachedResponse{Body: body, Header: quotaHeaders(response.Header), FetchedAt: c.cache.now()}
+		c.cache.set(req.Context(), key, cached, ttl)
+	}
+	return response, body, err
+}
+
+// send makes the request upstream and returns the raw body
+func (c *Client) send(req *retryablehttp.Request) (*Response, []byte, error) {
+	err := c.Wait(req.Context())
+	if err != nil {
+		return nil, nil, err
+	}
+
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	defer func() {
@@ -151,20 +260,19 @@ func (c *Client) Do(req *retryablehttp.Request, data interface{}) (*Response, er
 	}()
 
 	response := newResponse(resp)
+	if q := response.Quota(); q.Known {
+		c.mu.Lock()
+		c.quota = q
+		c.mu.Unlock()
+	}
 
 	c.configureOnce.Do(func() { c.configureRateLimiter(req.Context()) })
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return response, err
-	}
-
-	err = json.Unmarshal(bodyBytes, data)
-	if err != nil {
-		return response, err
+		return response, nil, err
 	}
-
-	return response, err
+	return response, bodyBytes, nil
 }
 
 func (c *Client) GetBaseUrl() *url.URL {
@@ -202,6 +310,19 @@ func (c *Client) NewGetRequest(requestUrl string, headers *map[string]string) (*
 
 type Response struct {
 	*http.Response
+
+	// Cached is true when the response was served from the client's cache
+	Cached bool
+}
+
+// clone copies the response and its headers for another caller
+func (r *Response) clone() *Response {
+	if r == nil || r.Response == nil {
+		return r
+	}
+	resp := *r.Response
+	resp.Header = r.Header.Clone()
+	return &Response{Response: &resp, Cached: r.Cached}
 }
 
 func newResponse(response *http.Response) *Response {
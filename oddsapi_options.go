@@ -3,7 +3,11 @@
 
 package oddsapi
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
 
 type ClientOption func(*Client) error
 
@@ -18,3 +22,63 @@ func SetPercentOfRateLimit(maxPercent float64) ClientOption {
 		return nil
 	}
 }
+
+// SetBaseUrl points the client at another host, such as a proxy or a test
+// server, instead of DefaultBaseUrl
+func SetBaseUrl(baseUrl string) ClientOption {
+	return func(c *Client) error {
+		return c.setBaseUrl(baseUrl)
+	}
+}
+
+// SetCache reuses responses of the endpoints in ttls for their duration,
+// keyed on the request params without the api key. A nil ttls uses
+// DefaultCacheTTLs. Responses are kept in memory unless SetCacheBackend is
+// also given.
+func SetCache(ttls CacheTTLs) ClientOption {
+	return func(c *Client) error {
+		if ttls == nil {
+			ttls = DefaultCacheTTLs
+		}
+		for endpoint, ttl := range ttls {
+			if ttl < 0 {
+				return fmt.Errorf("invalid cache ttl for %s: %s", endpoint, ttl)
+			}
+		}
+		var backend Cache
+		if c.cache != nil {
+			backend = c.cache.backend
+		}
+		c.cache = newResponseCache(ttls, backend)
+		return nil
+	}
+}
+
+// SetCacheBackend stores cached responses in backend, such as a shared disk
+// or Redis cache. It enables caching with DefaultCacheTTLs unless SetCache
+// is also given.
+func SetCacheBackend(backend Cache) ClientOption {
+	return func(c *Client) error {
+		if backend == nil {
+			return errors.New("cache backend is nil")
+		}
+		if c.cache == nil {
+			c.cache = newResponseCache(DefaultCacheTTLs, backend)
+			return nil
+		}
+		c.cache.backend = backend
+		return nil
+	}
+}
+
+// SetTransport sends requests through rt, such as a recording or replaying
+// transport from the cassette package, instead of the default transport
+func SetTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) error {
+		if rt == nil {
+			return errors.New("transport is nil")
+		}
+		c.client.HTTPClient.Transport = rt
+		return nil
+	}
+}
@@ -84,11 +84,14 @@ const (
 	MarketSpreads   MarketKey = "spreads"
 	MarketTotals    MarketKey = "totals"
 	MarketOutrights MarketKey = "outrights"
+
+	// MarketTeamTotals is only available from the event odds endpoint
+	MarketTeamTotals MarketKey = "team_totals"
 )
 
 func (m MarketKey) Valid() bool {
 	switch m {
-	case MarketH2H, MarketSpreads, MarketTotals, MarketOutrights:
+	case MarketH2H, MarketSpreads, MarketTotals, MarketOutrights, MarketTeamTotals:
 		return true
 	}
 	return false
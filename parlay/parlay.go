@@ -0,0 +1,170 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package parlay prices combinations of outcomes, including round robins.
+// Legs are treated as independent, so correlated legs on the same event are
+// flagged but not adjusted for.
+package parlay
+
+import (
+	"errors"
+	"fmt"
+	"oddsapi"
+	"oddsapi/devig"
+)
+
+type Leg struct {
+	Event     *oddsapi.Odds
+	BookMaker *oddsapi.BookMaker
+	Market    *oddsapi.Market
+	Outcome   *oddsapi.Outcome
+}
+
+// NewLeg looks up an outcome by bookmaker, market and outcome name. Point
+// selects the line on markets that quote several; nil matches any point.
+func NewLeg(event *oddsapi.Odds, bookMaker, market, outcome string, point *float64) (*Leg, error) {
+	for _, b := range event.BookMakers {
+		if b.Key != bookMaker {
+			continue
+		}
+		for _, m := range b.Markets {
+			if m.Key != market {
+				continue
+			}
+			for _, o := range m.Outcomes {
+				if o.Name != outcome {
+					continue
+				}
+				if point != nil && (o.Point == nil || *o.Point != *point) {
+					continue
+				}
+				return &Leg{Event: event, BookMaker: b, Market: m, Outcome: o}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("outcome %s not found in market %s at %s", outcome, market, bookMaker)
+}
+
+// Price is a single price quoted in every OddsFormat.
+type Price struct {
+	Decimal  float64
+	American float64
+}
+
+func newPrice(decimal float64) (Price, error) {
+	american, err := oddsapi.AmericanOddsFormat.FromDecimal(decimal)
+	if err != nil {
+		return Price{}, err
+	}
+	return Price{Decimal: decimal, American: american}, nil
+}
+
+type Parlay struct {
+	Legs []*Leg
+
+	Price              Price
+	ImpliedProbability float64
+
+	// FairProbability multiplies the de-vigged probability of each leg
+	FairProbability float64
+	FairPrice       Price
+
+	// EV is the expected return per unit staked at the fair probability
+	EV float64
+
+	// SameEvent is set when two or more legs are on the same event. Such
+	// legs are usually correlated, which the fair price does not reflect.
+	SameEvent bool
+}
+
+type Calculator struct {
+	// OddsFormat the prices were requested in. Defaults to decimal.
+	OddsFormat oddsapi.OddsFormat
+
+	// Method used to de-vig each leg's market. Defaults to multiplicative.
+	Method devig.Method
+}
+
+func NewCalculator(format oddsapi.OddsFormat) *Calculator {
+	return &Calculator{OddsFormat: format, Method: devig.DefaultMethod}
+}
+
+// Price combines legs into a single parlay. Two legs on the same market of
+// the same event are rejected because they cannot both be settled as
+// independent selections.
+func (c *Calculator) Price(legs ...*Leg) (*Parlay, error) {
+	if len(legs) < 2 {
+		return nil, errors.New("a parlay needs at least two legs")
+	}
+
+	p := &Parlay{Legs: legs}
+	decimal, fair := 1.0, 1.0
+	events := make(map[string]bool)
+	markets := make(map[string]bool)
+	for _, leg := range legs {
+		if leg == nil || leg.Event == nil || leg.Market == nil || leg.Outcome == nil {
+			return nil, errors.New("leg is missing its event, market or outcome")
+		}
+		key := leg.Event.Id + "|" + leg.Market.Key
+		if markets[key] {
+			return nil, fmt.Errorf("more than one leg on market %s of event %s", leg.Market.Key, leg.Event.Id)
+		}
+		markets[key] = true
+		if events[leg.Event.Id] {
+			p.SameEvent = true
+		}
+		events[leg.Event.Id] = true
+
+		d, err := c.OddsFormat.ToDecimal(leg.Outcome.Price)
+		if err != nil {
+			return nil, err
+		}
+		f, err := c.fairProbability(leg)
+		if err != nil {
+			return nil, err
+		}
+		decimal *= d
+		fair *= f
+	}
+
+	var err error
+	if p.Price, err = newPrice(decimal); err != nil {
+		return nil, err
+	}
+	if p.FairPrice, err = newPrice(1 / fair); err != nil {
+		return nil, err
+	}
+	p.ImpliedProbability = oddsapi.ImpliedProbability(decimal)
+	p.FairProbability = fair
+	p.EV = fair*decimal - 1
+	return p, nil
+}
+
+// fairProbability de-vigs the leg's market at the leg's line.
+func (c *Calculator) fairProbability(leg *Leg) (float64, error) {
+	line := leg.Event.LinePoint(leg.Market.Key, leg.Outcome)
+	var decimals []float64
+	index := -1
+	for _, o := range leg.Market.Outcomes {
+		point := leg.Event.LinePoint(leg.Market.Key, o)
+		if (point == nil) != (line == nil) || (point != nil && *point != *line) {
+			continue
+		}
+		d, err := c.OddsFormat.ToDecimal(o.Price)
+		if err != nil {
+			return 0, err
+		}
+		if o == leg.Outcome {
+			index = len(decimals)
+		}
+		decimals = append(decimals, d)
+	}
+	if index < 0 {
+		return 0, fmt.Errorf("outcome %s is not in market %s", leg.Outcome.Name, leg.Market.Key)
+	}
+	p, err := devig.Probabilities(decimals, c.Method)
+	if err != nil {
+		return 0, err
+	}
+	return p[index], nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package parlay
+
+import (
+	"math"
+	"oddsapi"
+	"testing"
+)
+
+func event(id string, home, away float64) *oddsapi.Odds {
+	return &oddsapi.Odds{
+		Id:       id,
+		HomeTeam: "Home " + id,
+		AwayTeam: "Away " + id,
+		BookMakers: []*oddsapi.BookMaker{{
+			Key: "book1",
+			Markets: []*oddsapi.Market{{
+				Key: "h2h",
+				Outcomes: []*oddsapi.Outcome{
+					{Name: "Home " + id, Price: home},
+					{Name: "Away " + id, Price: away},
+				},
+			}},
+		}},
+	}
+}
+
+func legs(t *testing.T, events ...*oddsapi.Odds) []*Leg {
+	var result []*Leg
+	for _, e := range events {
+		leg, err := NewLeg(e, "book1", "h2h", e.HomeTeam, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, leg)
+	}
+	return result
+}
+
+func TestCalculator_Price(t *testing.T) {
+	c := NewCalculator(oddsapi.DecimalOddsFormat)
+	p, err := c.Price(legs(t, event("a", 1.91, 1.91), event("b", 2.5, 1.5))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := 1.91 * 2.5
+	if math.Abs(p.Price.Decimal-expected) > 1e-9 {
+		t.Errorf("expected decimal %v, got %v", expected, p.Price.Decimal)
+	}
+	if math.Abs(p.Price.American-(expected-1)*100) > 1e-9 {
+		t.Errorf("expected american %v, got %v", (expected-1)*100, p.Price.American)
+	}
+
+	fairB := (1 / 2.5) / (1/2.5 + 1/1.5)
+	if math.Abs(p.FairProbability-0.5*fairB) > 1e-9 {
+		t.Errorf("expected fair probability %v, got %v", 0.5*fairB, p.FairProbability)
+	}
+	if p.FairPrice.Decimal <= p.Price.Decimal {
+		t.Error("expected the fair price to be longer than the offered price")
+	}
+	if p.SameEvent {
+		t.Error("expected legs on different events")
+	}
+}
+
+func TestCalculator_PriceRejectsSameMarket(t *testing.T) {
+	e := event("a", 1.91, 1.91)
+	home, _ := NewLeg(e, "book1", "h2h", "Home a", nil)
+	away, _ := NewLeg(e, "book1", "h2h", "Away a", nil)
+
+	_, err := NewCalculator(oddsapi.DecimalOddsFormat).Price(home, away)
+	if err == nil {
+		t.Error("expected an error for two legs on the same market")
+	}
+}
+
+func TestCalculator_RoundRobin(t *testing.T) {
+	c := NewCalculator(oddsapi.DecimalOddsFormat)
+	l := legs(t, event("a", 2, 1.8), event("b", 2, 1.8), event("c", 2, 1.8), event("d", 3, 1.4))
+
+	rr, err := c.RoundRobin(l, 10, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rr.Combinations) != 10 {
+		t.Fatalf("expected 6 doubles and 4 trebles, got %d combinations", len(rr.Combinations))
+	}
+	if rr.TotalStake != 100 {
+		t.Errorf("expected total stake 100, got %v", rr.TotalStake)
+	}
+
+	if rr.Payouts[0].MaxPayout != 0 || rr.Payouts[1].MaxPayout != 0 {
+		t.Error("expected no payout with fewer than two winners")
+	}
+	if rr.Payouts[2].MinPayout != 40 || rr.Payouts[2].MaxPayout != 60 {
+		t.Errorf("expected two winners to pay 40-60, got %v-%v", rr.Payouts[2].MinPayout, rr.Payouts[2].MaxPayout)
+	}
+
+	all := rr.Payouts[4]
+	if all.MinPayout != all.MaxPayout {
+		t.Error("expected a single payout when every leg wins")
+	}
+	if all.MaxProfit != all.MaxPayout-100 {
+		t.Errorf("expected profit to subtract the total stake")
+	}
+
+	if _, err = c.RoundRobin(l, 10, 5); err == nil {
+		t.Error("expected an error for a size larger than the number of legs")
+	}
+	if _, err = c.RoundRobin(l, 10, 2, 3, 2); err == nil {
+		t.Error("expected an error for a duplicate size")
+	}
+}
+
+func TestCalculator_RoundRobinMaxLegs(t *testing.T) {
+	var events []*oddsapi.Odds
+	for i := 0; i < 20; i++ {
+		events = append(events, event(string(rune('a'+i)), 2, 1.8))
+	}
+	rr, err := NewCalculator(oddsapi.DecimalOddsFormat).RoundRobin(legs(t, events...), 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rr.Combinations) != 184756 {
+		t.Fatalf("expected 184756 tens, got %d", len(rr.Combinations))
+	}
+	if rr.Payouts[9].MaxPayout != 0 {
+		t.Error("expected no payout with fewer than ten winners")
+	}
+	if rr.Payouts[10].MinPayout != 1024 || rr.Payouts[10].MaxPayout != 1024 {
+		t.Errorf("expected ten winners to pay a single ten, got %v-%v", rr.Payouts[10].MinPayout, rr.Payouts[10].MaxPayout)
+	}
+	if all := rr.Payouts[20]; math.Abs(all.MaxPayout-184756*1024) > 1e-6 {
+		t.Errorf("expected every ten to pay when all legs win, got %v", all.MaxPayout)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package parlay
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+type RoundRobin struct {
+	Sizes        []int
+	Combinations []*Parlay
+
+	// Stake is placed on every combination
+	Stake      float64
+	TotalStake float64
+
+	// Payouts has one row per number of winning legs, from zero to all
+	Payouts []*PayoutRow
+}
+
+// PayoutRow gives the range of returns when exactly Winners legs win. The
+// range exists because which legs win changes the combined prices.
+type PayoutRow struct {
+	Winners   int
+	MinPayout float64
+	MaxPayout float64
+	MinProfit float64
+	MaxProfit float64
+}
+
+// RoundRobin expands legs into every parlay of each size, e.g. sizes 2 and 3
+// for "2s and 3s", staking the same amount on each combination.
+func (c *Calculator) RoundRobin(legs []*Leg, stake float64, sizes ...int) (*RoundRobin, error) {
+	if len(sizes) == 0 {
+		return nil, errors.New("no round robin sizes provided")
+	}
+	if len(legs) > 20 {
+		return nil, fmt.Errorf("too many legs for a round robin: %d", len(legs))
+	}
+	sizes = append([]int(nil), sizes...)
+	sort.Ints(sizes)
+
+	rr := &RoundRobin{Sizes: sizes, Stake: stake}
+	var indexes [][]int
+	for i, size := range sizes {
+		if size < 2 || size > len(legs) {
+			return nil, fmt.Errorf("invalid round robin size %d for %d legs", size, len(legs))
+		}
+		if i > 0 && size == sizes[i-1] {
+			return nil, fmt.Errorf("duplicate round robin size %d", size)
+		}
+		for _, combo := range combinations(len(legs), size) {
+			selected := make([]*Leg, len(combo))
+			for i, idx := range combo {
+				selected[i] = legs[idx]
+			}
+			p, err := c.Price(selected...)
+			if err != nil {
+				return nil, err
+			}
+			rr.Combinations = append(rr.Combinations, p)
+			indexes = append(indexes, combo)
+		}
+	}
+	rr.TotalStake = stake * float64(len(rr.Combinations))
+
+	rr.Payouts = make([]*PayoutRow, len(legs)+1)
+	for i := range rr.Payouts {
+		rr.Payouts[i] = &PayoutRow{Winners: i, MinPayout: math.Inf(1)}
+	}
+	// payouts[mask] starts as the return of the combination of exactly those
+	// legs, then summing over subsets leaves the return of every
+	// combination won when those legs win
+	payouts := make([]float64, 1<<len(legs))
+	for i, combo := range indexes {
+		mask := 0
+		for _, idx := range combo {
+			mask |= 1 << idx
+		}
+		payouts[mask] += stake * rr.Combinations[i].Price.Decimal
+	}
+	for bit := 0; bit < len(legs); bit++ {
+		for mask := range payouts {
+			if mask&(1<<bit) != 0 {
+				payouts[mask] += payouts[mask^(1<<bit)]
+			}
+		}
+	}
+	for mask, payout := range payouts {
+		row := rr.Payouts[bits.OnesCount(uint(mask))]
+		row.MinPayout = math.Min(row.MinPayout, payout)
+		row.MaxPayout = math.Max(row.MaxPayout, payout)
+	}
+	for _, row := range rr.Payouts {
+		row.MinProfit = row.MinPayout - rr.TotalStake
+		row.MaxProfit = row.MaxPayout - rr.TotalStake
+	}
+	return rr, nil
+}
+
+// combinations returns every k-sized subset of 0..n-1 in lexical order.
+func combinations(n, k int) [][]int {
+	var result [][]int
+	combo := make([]int, k)
+	var walk func(start, depth int)
+	walk = func(start, depth int) {
+		if depth == k {
+			result = append(result, append([]int(nil), combo...))
+			return
+		}
+		for i := start; i <= n-(k-depth); i++ {
+			combo[depth] = i
+			walk(i+1, depth+1)
+		}
+	}
+	walk(0, 0)
+	return result
+}
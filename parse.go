@@ -0,0 +1,260 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// The Parse functions are the inverse of BuildPath. They accept a full
+// request url, or a path under any prefix such as a proxy's, and return the
+// params with every value validated by the same setters the client uses.
+// Values that were not in the query are left blank, so BuildPath fills in
+// the same defaults it would have for the original params.
+
+// apiPath returns the path segments after v4/sports
+func apiPath(u *url.URL) ([]string, error) {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "v4" && segments[i+1] == "sports" {
+			return segments[i+2:], nil
+		}
+	}
+	return nil, fmt.Errorf("not an odds api path: %s", u.Path)
+}
+
+// matchPath matches the segments after v4/sports against a pattern, where
+// blank pattern segments capture a value
+func matchPath(u *url.URL, pattern ...string) ([]string, error) {
+	segments, err := apiPath(u)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) != len(pattern) {
+		return nil, fmt.Errorf("unexpected path: %s", u.Path)
+	}
+	var values []string
+	for i, p := range pattern {
+		if p == "" {
+			if segments[i] == "" {
+				return nil, fmt.Errorf("blank path segment: %s", u.Path)
+			}
+			values = append(values, segments[i])
+		} else if segments[i] != p {
+			return nil, fmt.Errorf("unexpected path: %s", u.Path)
+		}
+	}
+	return values, nil
+}
+
+func queryList(q url.Values, key string) []string {
+	var values []string
+	for _, v := range strings.Split(q.Get(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func queryRegions(q url.Values) []Region {
+	var regions []Region
+	for _, r := range queryList(q, "regions") {
+		regions = append(regions, Region(r))
+	}
+	return regions
+}
+
+func queryMarkets(q url.Values) []MarketKey {
+	var markets []MarketKey
+	for _, m := range queryList(q, "markets") {
+		markets = append(markets, MarketKey(m))
+	}
+	return markets
+}
+
+func queryDateFormat(q url.Values) (DateFormat, error) {
+	d := DateFormat(q.Get("dateFormat"))
+	if d != "" && !d.Valid() {
+		return "", fmt.Errorf("invalid date format: %s", d)
+	}
+	return d, nil
+}
+
+func queryOddsFormat(q url.Values) (OddsFormat, error) {
+	o := OddsFormat(q.Get("oddsFormat"))
+	if o != "" && !o.Valid() {
+		return "", fmt.Errorf("invalid odds format: %s", o)
+	}
+	return o, nil
+}
+
+// queryCommenceTimes reads the commence time window. commentTimeTo is what
+// BuildPath encoded in earlier versions, so it is still accepted.
+func queryCommenceTimes(q url.Values, setFrom, setTo func(string) error) error {
+	if from := q.Get("commenceTimeFrom"); from != "" {
+		if err := setFrom(from); err != nil {
+			return err
+		}
+	}
+	to := q.Get("commenceTimeTo")
+	if to == "" {
+		to = q.Get("commentTimeTo")
+	}
+	if to != "" {
+		return setTo(to)
+	}
+	return nil
+}
+
+func ParseSportsParams(u *url.URL) (*SportsParams, error) {
+	if _, err := matchPath(u); err != nil {
+		return nil, err
+	}
+	return ParseSportsQuery(u.Query())
+}
+
+func ParseSportsQuery(q url.Values) (*SportsParams, error) {
+	return &SportsParams{ApiToken: q.Get("apiKey")}, nil
+}
+
+func ParseOddsParams(u *url.URL) (*OddsParams, error) {
+	values, err := matchPath(u, "", "odds")
+	if err != nil {
+		return nil, err
+	}
+	return ParseOddsQuery(values[0], u.Query())
+}
+
+func ParseOddsQuery(sportKey string, q url.Values) (*OddsParams, error) {
+	if sportKey == "" {
+		return nil, errors.New("sports key is blank")
+	}
+	params := NewOddsParams(q.Get("apiKey"), sportKey)
+	err := params.SetRegions(queryRegions(q)...)
+	if err != nil {
+		return nil, err
+	}
+	if err = params.SetMarkets(queryMarkets(q)...); err != nil {
+		return nil, err
+	}
+	if params.DateFormat, err = queryDateFormat(q); err != nil {
+		return nil, err
+	}
+	if params.OddsFormat, err = queryOddsFormat(q); err != nil {
+		return nil, err
+	}
+	if ids := queryList(q, "eventIds"); ids != nil {
+		params.SetEventIds(ids...)
+	}
+	if b := queryList(q, "bookmakers"); b != nil {
+		params.SetBookmakers(b...)
+	}
+	err = queryCommenceTimes(q, params.SetCommenceTimeFromISO, params.SetCommenceTimeToISO)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func ParseEventParams(u *url.URL) (*EventParams, error) {
+	values, err := matchPath(u, "", "events")
+	if err != nil {
+		return nil, err
+	}
+	return ParseEventQuery(values[0], u.Query())
+}
+
+func ParseEventQuery(sportKey string, q url.Values) (*EventParams, error) {
+	if sportKey == "" {
+		return nil, errors.New("no sports key provided")
+	}
+	params := &EventParams{ApiToken: q.Get("apiKey"), SportKey: sportKey}
+	var err error
+	if params.DateFormat, err = queryDateFormat(q); err != nil {
+		return nil, err
+	}
+	if ids := queryList(q, "eventIds"); ids != nil {
+		params.SetEventIds(ids...)
+	}
+	err = queryCommenceTimes(q, params.SetCommenceTimeFromISO, params.SetCommenceTimeToISO)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func ParseEventOddsParams(u *url.URL) (*EventOddsParams, error) {
+	values, err := matchPath(u, "", "events", "", "odds")
+	if err != nil {
+		return nil, err
+	}
+	return ParseEventOddsQuery(values[0], values[1], u.Query())
+}
+
+func ParseEventOddsQuery(sportKey, eventKey string, q url.Values) (*EventOddsParams, error) {
+	if sportKey == "" {
+		return nil, errors.New("sports key is empty")
+	} else if eventKey == "" {
+		return nil, errors.New("event key is empty")
+	}
+	params := &EventOddsParams{ApiToken: q.Get("apiKey"), SportKey: sportKey, EventKey: eventKey}
+	if r := queryRegions(q); r != nil {
+		if err := params.SetRegions(r...); err != nil {
+			return nil, err
+		}
+	}
+	if m := queryMarkets(q); m != nil {
+		if err := params.SetMarkets(m...); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if params.DateFormat, err = queryDateFormat(q); err != nil {
+		return nil, err
+	}
+	if params.OddsFormat, err = queryOddsFormat(q); err != nil {
+		return nil, err
+	}
+	if b := queryList(q, "bookmakers"); b != nil {
+		params.SetBookmakers(b...)
+	}
+	return params, nil
+}
+
+func ParseScoresParams(u *url.URL) (*ScoresParams, error) {
+	values, err := matchPath(u, "", "scores")
+	if err != nil {
+		return nil, err
+	}
+	return ParseScoresQuery(values[0], u.Query())
+}
+
+func ParseScoresQuery(sportKey string, q url.Values) (*ScoresParams, error) {
+	if sportKey == "" {
+		return nil, errors.New("sports key is blank")
+	}
+	params := &ScoresParams{ApiToken: q.Get("apiKey"), SportKey: sportKey}
+	if days := q.Get("daysFrom"); days != "" {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return nil, fmt.Errorf("invalid days from: %s", days)
+		}
+		if err = params.SetDaysFrom(n); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if params.DateFormat, err = queryDateFormat(q); err != nil {
+		return nil, err
+	}
+	if ids := queryList(q, "eventIds"); ids != nil {
+		params.SetEventIds(ids...)
+	}
+	return params, nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"net/url"
+	"testing"
+)
+
+func roundTrip(t *testing.T, params Params, parse func(*url.URL) (Params, error)) {
+	t.Helper()
+	base, _ := url.Parse(DefaultBaseUrl)
+	built, err := params.BuildPath(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(built)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parse(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuilt, err := parsed.BuildPath(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rebuilt != built {
+		t.Errorf("expected %s, got %s", built, rebuilt)
+	}
+}
+
+func TestParseParams_RoundTrip(t *testing.T) {
+	odds := NewOddsParams("key", "basketball_nba")
+	_ = odds.SetRegions(RegionUs, RegionUk)
+	_ = odds.SetMarkets(MarketH2H, MarketSpreads)
+	odds.SetBookmakers("pinnacle", "draftkings")
+	odds.SetEventIds("event1")
+	odds.OddsFormat = AmericanOddsFormat
+	_ = odds.SetCommenceTimeFromISO("2024-01-01T00:00:00Z")
+	_ = odds.SetCommenceTimeToISO("2024-01-02T00:00:00Z")
+	roundTrip(t, odds, func(u *url.URL) (Params, error) { return ParseOddsParams(u) })
+
+	events := &EventParams{ApiToken: "key", SportKey: "basketball_nba"}
+	events.SetEventIds("event1", "event2")
+	_ = events.SetCommenceTimeToISO("2024-01-02T00:00:00Z")
+	roundTrip(t, events, func(u *url.URL) (Params, error) { return ParseEventParams(u) })
+
+	base, _ := url.Parse(DefaultBaseUrl)
+	for _, p := range []Params{odds, events} {
+		built, err := p.BuildPath(base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, _ := url.Parse(built)
+		if q := u.Query(); q.Get("commenceTimeTo") != "2024-01-02T00:00:00Z" || q.Has("commentTimeTo") {
+			t.Errorf("expected the window to be encoded as commenceTimeTo, got %s", u.RawQuery)
+		}
+	}
+
+	eventOdds := &EventOddsParams{ApiToken: "key", SportKey: "basketball_nba", EventKey: "event1"}
+	_ = eventOdds.SetMarkets(MarketTeamTotals)
+	roundTrip(t, eventOdds, func(u *url.URL) (Params, error) { return ParseEventOddsParams(u) })
+
+	scores := &ScoresParams{ApiToken: "key", SportKey: "basketball_nba", DaysFrom: 2}
+	roundTrip(t, scores, func(u *url.URL) (Params, error) { return ParseScoresParams(u) })
+
+	roundTrip(t, &SportsParams{ApiToken: "key"}, func(u *url.URL) (Params, error) { return ParseSportsParams(u) })
+}
+
+func TestParseOddsParams(t *testing.T) {
+	u, _ := url.Parse("/proxy/v4/sports/basketball_nba/odds?apiKey=key&regions=us,%20uk&commenceTimeTo=2024-01-02T00:00:00Z")
+	params, err := ParseOddsParams(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.SportKey != "basketball_nba" || params.ApiToken != "key" || params.Region != "us,uk" {
+		t.Errorf("unexpected params %+v", params)
+	}
+	if params.CommenceTimeTo == nil || *params.CommenceTimeTo != "2024-01-02T00:00:00Z" {
+		t.Errorf("expected the commence time window to be parsed")
+	}
+
+	u, _ = url.Parse("/v4/sports/basketball_nba/odds?regions=us&commentTimeTo=2024-01-02T00:00:00Z")
+	if params, err = ParseOddsParams(u); err != nil {
+		t.Fatal(err)
+	}
+	if params.CommenceTimeTo == nil || *params.CommenceTimeTo != "2024-01-02T00:00:00Z" {
+		t.Errorf("expected the old commentTimeTo spelling to be accepted")
+	}
+
+	invalid := []string{
+		"/v4/sports/basketball_nba/odds?regions=mars",
+		"/v4/sports/basketball_nba/odds?markets=moneyline",
+		"/v4/sports/basketball_nba/odds?oddsFormat=fractional",
+		"/v4/sports/basketball_nba/odds?commenceTimeFrom=tomorrow",
+		"/v4/sports/basketball_nba/events",
+		"/v3/sports/basketball_nba/odds",
+	}
+	for _, s := range invalid {
+		u, _ = url.Parse(s)
+		if _, err = ParseOddsParams(u); err == nil {
+			t.Errorf("expected an error parsing %s", s)
+		}
+	}
+}
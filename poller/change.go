@@ -0,0 +1,77 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package poller
+
+import (
+	"oddsapi"
+	"oddsapi/diff"
+	"time"
+)
+
+type ChangeKind string
+
+const (
+	EventAdded          ChangeKind = "event_added"
+	EventRemoved        ChangeKind = "event_removed"
+	CommenceTimeChanged ChangeKind = "commence_time_changed"
+	BookMakerAdded      ChangeKind = "bookmaker_added"
+	BookMakerRemoved    ChangeKind = "bookmaker_removed"
+	MarketOpened        ChangeKind = "market_opened"
+	MarketClosed        ChangeKind = "market_closed"
+	OutcomeChanged      ChangeKind = "outcome_changed"
+	Error               ChangeKind = "error"
+	QuotaExhausted      ChangeKind = "quota_exhausted"
+)
+
+type Change struct {
+	Kind ChangeKind
+	Time time.Time
+
+	EventId   string
+	BookMaker string
+	Market    string
+
+	// Event is set for added and removed events
+	Event *oddsapi.Odds
+
+	// CommenceTime is set when the start time moved
+	CommenceTime *diff.StringChange
+
+	// Outcome is set for outcome changes
+	Outcome *diff.OutcomeChange
+
+	// Err is set for errors and when the quota runs out
+	Err error
+}
+
+func newChanges(d *diff.Diff, now time.Time) []*Change {
+	var result []*Change
+	for _, e := range d.Added {
+		result = append(result, &Change{Kind: EventAdded, Time: now, EventId: e.Id, Event: e})
+	}
+	for _, e := range d.Removed {
+		result = append(result, &Change{Kind: EventRemoved, Time: now, EventId: e.Id, Event: e})
+	}
+	for _, ed := range d.Changed {
+		if ed.CommenceTime != nil {
+			result = append(result, &Change{Kind: CommenceTimeChanged, Time: now, EventId: ed.EventId, CommenceTime: ed.CommenceTime})
+		}
+		for _, b := range ed.BookMakersAdded {
+			result = append(result, &Change{Kind: BookMakerAdded, Time: now, EventId: ed.EventId, BookMaker: b})
+		}
+		for _, b := range ed.BookMakersRemoved {
+			result = append(result, &Change{Kind: BookMakerRemoved, Time: now, EventId: ed.EventId, BookMaker: b})
+		}
+		for _, m := range ed.MarketsOpened {
+			result = append(result, &Change{Kind: MarketOpened, Time: now, EventId: ed.EventId, BookMaker: m.BookMaker, Market: m.Market})
+		}
+		for _, m := range ed.MarketsClosed {
+			result = append(result, &Change{Kind: MarketClosed, Time: now, EventId: ed.EventId, BookMaker: m.BookMaker, Market: m.Market})
+		}
+		for _, o := range ed.Outcomes {
+			result = append(result, &Change{Kind: OutcomeChanged, Time: now, EventId: ed.EventId, BookMaker: o.BookMaker, Market: o.Market, Outcome: o})
+		}
+	}
+	return result
+}
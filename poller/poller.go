@@ -0,0 +1,148 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package poller repeatedly fetches odds, diffs each snapshot against the
+// previous one and emits the changes on a channel.
+package poller
+
+import (
+	"context"
+	"fmt"
+	"oddsapi"
+	"oddsapi/diff"
+	"time"
+)
+
+const (
+	DefaultInterval   = time.Minute
+	DefaultMaxBackoff = 10 * time.Minute
+)
+
+// Fetcher returns one snapshot of odds along with the response that carried it.
+type Fetcher func(ctx context.Context) ([]*oddsapi.Odds, *oddsapi.Response, error)
+
+func OddsFetcher(s *oddsapi.OddsService, params *oddsapi.OddsParams) Fetcher {
+	return func(ctx context.Context) ([]*oddsapi.Odds, *oddsapi.Response, error) {
+		return s.GetOdds(params)
+	}
+}
+
+// EventOddsFetcher combines the odds of each event into a single snapshot.
+func EventOddsFetcher(s *oddsapi.EventOddsService, params ...*oddsapi.EventOddsParams) Fetcher {
+	return func(ctx context.Context) ([]*oddsapi.Odds, *oddsapi.Response, error) {
+		var (
+			result []*oddsapi.Odds
+			resp   *oddsapi.Response
+		)
+		for _, p := range params {
+			if err := ctx.Err(); err != nil {
+				return nil, resp, err
+			}
+			data, r, err := s.GetOdds(p)
+			resp = r
+			if err != nil {
+				return nil, resp, err
+			}
+			if data != nil {
+				result = append(result, data)
+			}
+		}
+		return result, resp, nil
+	}
+}
+
+type Poller struct {
+	Interval time.Duration
+
+	// MaxBackoff caps the delay between attempts after consecutive errors
+	MaxBackoff time.Duration
+
+	// MinRemaining stops the poller once the reported quota drops to it or
+	// lower. Zero stops once the quota is exhausted.
+	MinRemaining int
+
+	fetch Fetcher
+	now   func() time.Time
+}
+
+// New creates a Poller. Fetchers built on the services share the client's
+// rate limiter, so polls never exceed it however short the interval.
+func New(fetch Fetcher, interval time.Duration) *Poller {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Poller{
+		Interval:   interval,
+		MaxBackoff: DefaultMaxBackoff,
+		fetch:      fetch,
+		now:        time.Now,
+	}
+}
+
+// Start polls until ctx is cancelled or the quota runs out, then closes the
+// returned channel. The first successful poll reports every event as added.
+func (p *Poller) Start(ctx context.Context) <-chan *Change {
+	changes := make(chan *Change)
+	go p.run(ctx, changes)
+	return changes
+}
+
+func (p *Poller) run(ctx context.Context, changes chan<- *Change) {
+	defer close(changes)
+
+	var (
+		prev     []*oddsapi.Odds
+		failures int
+	)
+	for {
+		delay := p.Interval
+		snapshot, resp, err := p.fetch(ctx)
+		if err == nil {
+			failures = 0
+			for _, c := range newChanges(diff.Compare(prev, snapshot), p.now()) {
+				if !send(ctx, changes, c) {
+					return
+				}
+			}
+			prev = snapshot
+		} else if ctx.Err() == nil {
+			failures++
+			delay = p.backoff(failures)
+			if !send(ctx, changes, &Change{Kind: Error, Time: p.now(), Err: err}) {
+				return
+			}
+		}
+
+		if q := resp.Quota(); q.Known && q.Remaining <= p.MinRemaining {
+			err = fmt.Errorf("quota remaining %d is at or below the minimum %d", q.Remaining, p.MinRemaining)
+			send(ctx, changes, &Change{Kind: QuotaExhausted, Time: p.now(), Err: err})
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (p *Poller) backoff(failures int) time.Duration {
+	delay := p.Interval
+	for i := 0; i < failures && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+func send(ctx context.Context, changes chan<- *Change, c *Change) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case changes <- c:
+		return true
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package poller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"oddsapi"
+	"testing"
+	"time"
+)
+
+func response(remaining string) *oddsapi.Response {
+	h := make(http.Header)
+	h.Set("X-Requests-Remaining", remaining)
+	h.Set("X-Requests-Used", "10")
+	h.Set("X-Requests-Last", "1")
+	return &oddsapi.Response{Response: &http.Response{StatusCode: 200, Header: h}}
+}
+
+func snapshot(price float64) []*oddsapi.Odds {
+	return []*oddsapi.Odds{{
+		Id: "event1",
+		BookMakers: []*oddsapi.BookMaker{{
+			Key: "book1",
+			Markets: []*oddsapi.Market{{
+				Key:      "h2h",
+				Outcomes: []*oddsapi.Outcome{{Name: "Home", Price: price}},
+			}},
+		}},
+	}}
+}
+
+func TestPoller_EmitsChanges(t *testing.T) {
+	results := []func() ([]*oddsapi.Odds, *oddsapi.Response, error){
+		func() ([]*oddsapi.Odds, *oddsapi.Response, error) { return snapshot(2), response("100"), nil },
+		func() ([]*oddsapi.Odds, *oddsapi.Response, error) { return nil, nil, errors.New("upstream error") },
+		func() ([]*oddsapi.Odds, *oddsapi.Response, error) { return snapshot(2), response("99"), nil },
+		func() ([]*oddsapi.Odds, *oddsapi.Response, error) { return snapshot(2.1), response("5"), nil },
+	}
+	var calls int
+	fetch := func(ctx context.Context) ([]*oddsapi.Odds, *oddsapi.Response, error) {
+		r := results[calls]
+		calls++
+		return r()
+	}
+
+	p := New(fetch, time.Millisecond)
+	p.MaxBackoff = 2 * time.Millisecond
+	p.MinRemaining = 10
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var kinds []ChangeKind
+	var moved *Change
+	for c := range p.Start(ctx) {
+		kinds = append(kinds, c.Kind)
+		if c.Kind == OutcomeChanged {
+			moved = c
+		}
+	}
+
+	expected := []ChangeKind{EventAdded, Error, OutcomeChanged, QuotaExhausted}
+	if len(kinds) != len(expected) {
+		t.Fatalf("expected changes %v, got %v", expected, kinds)
+	}
+	for i := range expected {
+		if kinds[i] != expected[i] {
+			t.Errorf("expected change %d to be %s, got %s", i, expected[i], kinds[i])
+		}
+	}
+	if moved == nil || moved.Outcome.New.Price != 2.1 || moved.BookMaker != "book1" {
+		t.Errorf("expected a move to 2.1 at book1, got %+v", moved)
+	}
+	if calls != 4 {
+		t.Errorf("expected polling to stop after 4 calls, got %d", calls)
+	}
+}
+
+func TestPoller_StopsOnCancel(t *testing.T) {
+	fetch := func(ctx context.Context) ([]*oddsapi.Odds, *oddsapi.Response, error) {
+		return snapshot(2), nil, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := New(fetch, time.Hour).Start(ctx)
+
+	if c := <-changes; c.Kind != EventAdded {
+		t.Errorf("expected the first change to add the event, got %s", c.Kind)
+	}
+	cancel()
+
+	select {
+	case _, ok := <-changes:
+		if ok {
+			t.Error("expected no further changes after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the channel to close after cancel")
+	}
+}
+
+func TestPoller_StopsWhenExhausted(t *testing.T) {
+	remaining := []string{"2", "1", "0", "0"}
+	var calls int
+	fetch := func(ctx context.Context) ([]*oddsapi.Odds, *oddsapi.Response, error) {
+		r := response(remaining[calls])
+		calls++
+		return snapshot(2), r, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var last *Change
+	for c := range New(fetch, time.Millisecond).Start(ctx) {
+		last = c
+	}
+	if last == nil || last.Kind != QuotaExhausted {
+		t.Fatalf("expected the poller to stop with %s, got %+v", QuotaExhausted, last)
+	}
+	if calls != 3 {
+		t.Errorf("expected polling to stop after 3 calls, got %d", calls)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"fmt"
+	"math"
+)
+
+// ToDecimal converts a price quoted in this OddsFormat to decimal odds.
+func (o OddsFormat) ToDecimal(price float64) (float64, error) {
+	switch o {
+	case DecimalOddsFormat, "":
+		if price <= 1 {
+			return 0, fmt.Errorf("invalid decimal price: %v", price)
+		}
+		return price, nil
+	case AmericanOddsFormat:
+		if price >= 100 {
+			return 1 + price/100, nil
+		} else if price <= -100 {
+			return 1 + 100/math.Abs(price), nil
+		}
+		return 0, fmt.Errorf("invalid american price: %v", price)
+	}
+	return 0, fmt.Errorf("invalid odds format: %s", o)
+}
+
+// FromDecimal converts decimal odds to a price quoted in this OddsFormat.
+func (o OddsFormat) FromDecimal(decimal float64) (float64, error) {
+	if decimal <= 1 {
+		return 0, fmt.Errorf("invalid decimal price: %v", decimal)
+	}
+	switch o {
+	case DecimalOddsFormat, "":
+		return decimal, nil
+	case AmericanOddsFormat:
+		if decimal >= 2 {
+			return (decimal - 1) * 100, nil
+		}
+		return -100 / (decimal - 1), nil
+	}
+	return 0, fmt.Errorf("invalid odds format: %s", o)
+}
+
+// ImpliedProbability returns the break-even probability of decimal odds.
+func ImpliedProbability(decimal float64) float64 {
+	if decimal <= 0 {
+		return 0
+	}
+	return 1 / decimal
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOddsFormat_ToDecimal(t *testing.T) {
+	cases := []struct {
+		format   OddsFormat
+		price    float64
+		expected float64
+	}{
+		{DecimalOddsFormat, 1.91, 1.91},
+		{AmericanOddsFormat, 150, 2.5},
+		{AmericanOddsFormat, -200, 1.5},
+		{AmericanOddsFormat, 100, 2},
+	}
+	for _, c := range cases {
+		result, err := c.format.ToDecimal(c.price)
+		if err != nil {
+			t.Error(err)
+		}
+		if math.Abs(result-c.expected) > 1e-9 {
+			t.Errorf("expected %v %s to be %v decimal, got %v", c.price, c.format, c.expected, result)
+		}
+	}
+
+	if _, err := AmericanOddsFormat.ToDecimal(50); err == nil {
+		t.Error("expected an error for american price 50")
+	}
+	if _, err := DecimalOddsFormat.ToDecimal(0.5); err == nil {
+		t.Error("expected an error for decimal price 0.5")
+	}
+}
+
+func TestOddsFormat_FromDecimal(t *testing.T) {
+	result, err := AmericanOddsFormat.FromDecimal(2.5)
+	if err != nil {
+		t.Error(err)
+	}
+	if math.Abs(result-150) > 1e-9 {
+		t.Errorf("expected 150, got %v", result)
+	}
+
+	result, err = AmericanOddsFormat.FromDecimal(1.5)
+	if err != nil {
+		t.Error(err)
+	}
+	if math.Abs(result+200) > 1e-9 {
+		t.Errorf("expected -200, got %v", result)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Consumer is an internal client of the proxy. It authenticates with its own
+// token, passed as the apiKey query parameter like the real key or as a
+// bearer token, so an unmodified oddsapi.Client can use the proxy.
+type Consumer struct {
+	Name  string `yaml:"name" toml:"name"`
+	Token string `yaml:"token" toml:"token"`
+
+	// Limit is the quota the consumer may spend each Period. Zero is no
+	// limit and a zero Period never resets.
+	Limit  int           `yaml:"limit" toml:"limit"`
+	Period time.Duration `yaml:"period" toml:"period"`
+
+	// Admin consumers can see the usage of every consumer
+	Admin bool `yaml:"admin" toml:"admin"`
+}
+
+type Usage struct {
+	Name string `json:"name"`
+
+	// Used is the upstream quota spent in the current period
+	Used        int       `json:"used"`
+	Limit       int       `json:"limit,omitempty"`
+	Requests    int       `json:"requests"`
+	CacheHits   int       `json:"cache_hits"`
+	Rejected    int       `json:"rejected"`
+	PeriodStart time.Time `json:"period_start"`
+}
+
+type account struct {
+	consumer *Consumer
+	usage    Usage
+}
+
+type accountKey struct{}
+
+// LoadConsumers reads a YAML or TOML file with a list of consumers
+func LoadConsumers(path string) ([]*Consumer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Consumers []*Consumer `yaml:"consumers" toml:"consumers"`
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	case ".toml":
+		err = toml.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("unsupported consumers format: %s", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing consumers %s: %w", path, err)
+	}
+	return file.Consumers, nil
+}
+
+// AddConsumer registers a consumer. Once any consumer is added every request
+// must carry a known token.
+func (s *Server) AddConsumer(c *Consumer) error {
+	if c.Name == "" || c.Token == "" {
+		return errors.New("consumer needs a name and a token")
+	}
+	if c.Limit < 0 || c.Period < 0 {
+		return fmt.Errorf("invalid limit for consumer %s", c.Name)
+	}
+	s.amu.Lock()
+	defer s.amu.Unlock()
+	if _, ok := s.accounts[c.Token]; ok {
+		return fmt.Errorf("duplicate token for consumer %s", c.Name)
+	}
+	for _, a := range s.accounts {
+		if a.consumer.Name == c.Name {
+			return fmt.Errorf("duplicate consumer %s", c.Name)
+		}
+	}
+	s.accounts[c.Token] = &account{
+		consumer: c,
+		usage:    Usage{Name: c.Name, Limit: c.Limit, PeriodStart: s.now()},
+	}
+	return nil
+}
+
+// Usage reports the usage of every consumer, ordered by name
+func (s *Server) Usage() []Usage {
+	s.amu.Lock()
+	defer s.amu.Unlock()
+	now := s.now()
+	usage := make([]Usage, 0, len(s.accounts))
+	for _, a := range s.accounts {
+		a.reset(now)
+		usage = append(usage, a.usage)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].Name < usage[j].Name
+	})
+	return usage
+}
+
+func (s *Server) authenticate(r *http.Request) (*account, bool) {
+	token := r.URL.Query().Get("apiKey")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token = bearer
+	}
+	s.amu.Lock()
+	defer s.amu.Unlock()
+	a, ok := s.accounts[token]
+	return a, ok
+}
+
+func (s *Server) hasConsumers() bool {
+	s.amu.Lock()
+	defer s.amu.Unlock()
+	return len(s.accounts) > 0
+}
+
+func requestAccount(r *http.Request) *account {
+	a, _ := r.Context().Value(accountKey{}).(*account)
+	return a
+}
+
+func withAccount(r *http.Request, a *account) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), accountKey{}, a))
+}
+
+// reset starts a new period once the current one has passed
+func (a *account) reset(now time.Time) {
+	p := a.consumer.Period
+	if p <= 0 || now.Before(a.usage.PeriodStart.Add(p)) {
+		return
+	}
+	a.usage.Used = 0
+	a.usage.PeriodStart = now.Truncate(p)
+}
+
+// allow reports whether the account can spend cost more quota this period
+// and reserves it, so concurrent requests cannot overshoot the limit together
+func (s *Server) allow(a *account, cost int) bool {
+	if a == nil {
+		return true
+	}
+	s.amu.Lock()
+	defer s.amu.Unlock()
+	a.reset(s.now())
+	if a.consumer.Limit > 0 && a.usage.Used+cost > a.consumer.Limit {
+		a.usage.Rejected++
+		return false
+	}
+	a.usage.Used += cost
+	return true
+}
+
+// record counts a request and replaces the cost reserved by allow with the
+// cost actually spent upstream
+func (s *Server) record(a *account, reserved, cost int, hit bool) {
+	if a == nil {
+		return
+	}
+	s.amu.Lock()
+	defer s.amu.Unlock()
+	a.reset(s.now())
+	a.usage.Requests++
+	// the reservation may belong to a period that has since been reset
+	a.usage.Used = max(a.usage.Used+cost-reserved, 0)
+	if hit {
+		a.usage.CacheHits++
+	}
+}
+
+// usage reports every consumer to admins and only their own to others
+func (s *Server) usage(w http.ResponseWriter, r *http.Request) {
+	a := requestAccount(r)
+	if a == nil {
+		writeError(w, http.StatusNotFound, errors.New("no consumers configured"))
+		return
+	}
+	usage := s.Usage()
+	if !a.consumer.Admin {
+		own := usage[:0]
+		for _, u := range usage {
+			if u.Name == a.consumer.Name {
+				own = append(own, u)
+			}
+		}
+		usage = own
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(usage)
+}
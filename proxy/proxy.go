@@ -0,0 +1,286 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package proxy serves the /v4 paths of the Odds API to internal consumers
+// from a single Client, so many services can share one api key. Responses are
+// cached for a TTL and identical concurrent requests share one upstream call.
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"golang.org/x/sync/singleflight"
+	"net/http"
+	"net/url"
+	"oddsapi"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const DefaultTTL = 30 * time.Second
+
+// Quota headers as sent by the API. Cached responses report the latest quota
+// with a cost of zero.
+const (
+	HeaderRequestsRemaining = "X-Requests-Remaining"
+	HeaderRequestsUsed      = "X-Requests-Used"
+	HeaderRequestsLast      = "X-Requests-Last"
+	HeaderCache             = "X-Cache"
+)
+
+type entry struct {
+	body    []byte
+	quota   oddsapi.Quota
+	expires time.Time
+}
+
+// upstreamError keeps the status of a failed upstream request so it can be
+// passed on to the consumer
+type upstreamError struct {
+	status int
+	err    error
+}
+
+func (e *upstreamError) Error() string {
+	return e.err.Error()
+}
+
+type Server struct {
+	TTL time.Duration
+
+	client *oddsapi.Client
+	mux    *http.ServeMux
+	group  singleflight.Group
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*entry
+
+	amu      sync.Mutex
+	accounts map[string]*account
+}
+
+// New creates a Server on the client, whose api key is injected into every
+// upstream request. A ttl of zero disables caching but still coalesces.
+func New(client *oddsapi.Client, ttl time.Duration) *Server {
+	s := &Server{
+		TTL:    ttl,
+		client: client,
+		mux:    http.NewServeMux(),
+		now:    time.Now,
+		cache:  make(map[string]*entry),
+
+		accounts: make(map[string]*account),
+	}
+	s.mux.HandleFunc("GET /v4/sports", s.sports)
+	s.mux.HandleFunc("GET /v4/sports/{$}", s.sports)
+	s.mux.HandleFunc("GET /v4/sports/{sport}/odds", s.odds)
+	s.mux.HandleFunc("GET /v4/sports/{sport}/odds/{$}", s.odds)
+	s.mux.HandleFunc("GET /v4/sports/{sport}/events", s.events)
+	s.mux.HandleFunc("GET /v4/sports/{sport}/events/{$}", s.events)
+	s.mux.HandleFunc("GET /v4/sports/{sport}/events/{event}/odds", s.eventOdds)
+	s.mux.HandleFunc("GET /v4/sports/{sport}/events/{event}/odds/{$}", s.eventOdds)
+	s.mux.HandleFunc("GET /v4/sports/{sport}/scores", s.scores)
+	s.mux.HandleFunc("GET /v4/sports/{sport}/scores/{$}", s.scores)
+	s.mux.HandleFunc("GET /usage", s.usage)
+	return s
+}
+
+// ServeHTTP authenticates the consumer when any are registered
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.hasConsumers() {
+		a, ok := s.authenticate(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, errors.New("unknown consumer token"))
+			return
+		}
+		r = withAccount(r, a)
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) sports(w http.ResponseWriter, r *http.Request) {
+	params, err := oddsapi.ParseSportsParams(r.URL)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	params.ApiToken = s.client.GetApiToken()
+	s.serve(w, r, params)
+}
+
+func (s *Server) odds(w http.ResponseWriter, r *http.Request) {
+	params, err := oddsapi.ParseOddsParams(r.URL)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	params.ApiToken = s.client.GetApiToken()
+	s.serve(w, r, params)
+}
+
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	params, err := oddsapi.ParseEventParams(r.URL)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	params.ApiToken = s.client.GetApiToken()
+	s.serve(w, r, params)
+}
+
+func (s *Server) eventOdds(w http.ResponseWriter, r *http.Request) {
+	params, err := oddsapi.ParseEventOddsParams(r.URL)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	params.ApiToken = s.client.GetApiToken()
+	s.serve(w, r, params)
+}
+
+func (s *Server) scores(w http.ResponseWriter, r *http.Request) {
+	params, err := oddsapi.ParseScoresParams(r.URL)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	params.ApiToken = s.client.GetApiToken()
+	s.serve(w, r, params)
+}
+
+// serve answers from the cache or a single upstream call shared by every
+// identical request in flight. Entries are keyed by oddsapi.CanonicalKey, so
+// queries that differ only in order or apiKey share one, and the upstream
+// body is passed through unchanged. The consumer whose request reached
+// upstream is charged its cost, and cache hits are free. The estimated cost
+// is reserved up front and settled once upstream reports the actual cost.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, params oddsapi.Params) {
+	path, err := params.BuildPath(s.client.GetBaseUrl())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	key := oddsapi.CanonicalKey(u)
+	a := requestAccount(r)
+	if e, ok := s.lookup(key); ok {
+		s.record(a, 0, 0, true)
+		s.write(w, e, true)
+		return
+	}
+
+	var cost int
+	if c, ok := params.(interface{ Cost() int }); ok {
+		cost = c.Cost()
+	}
+	if !s.allow(a, cost) {
+		writeError(w, http.StatusTooManyRequests, errors.New("consumer quota exceeded"))
+		return
+	}
+
+	charged := false
+	v, err, _ := s.group.Do(key, func() (any, error) {
+		if e, ok := s.lookup(key); ok {
+			return e, nil
+		}
+		charged = true
+		body, resp, err := s.fetch(path)
+		s.record(a, cost, resp.Quota().Last, false)
+		if resp != nil && resp.StatusCode != http.StatusOK {
+			return nil, &upstreamError{status: resp.StatusCode, err: errors.New("upstream returned " + resp.Status)}
+		}
+		if err != nil {
+			return nil, &upstreamError{status: http.StatusBadGateway, err: err}
+		}
+		e := &entry{body: body, quota: resp.Quota(), expires: s.now().Add(s.TTL)}
+		s.store(key, e)
+		return e, nil
+	})
+	if !charged {
+		s.record(a, cost, 0, false)
+	}
+	if err != nil {
+		var ue *upstreamError
+		if errors.As(err, &ue) {
+			writeError(w, ue.status, ue)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.write(w, v.(*entry), false)
+}
+
+// fetch requests path through the client and returns the raw body, so
+// fields the client does not model reach consumers too
+func (s *Server) fetch(path string) (json.RawMessage, *oddsapi.Response, error) {
+	req, err := s.client.NewGetRequest(path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var body json.RawMessage
+	resp, err := s.client.Do(req, &body)
+	return body, resp, err
+}
+
+func (s *Server) lookup(key string) (*entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.cache[key]
+	if !ok {
+		return nil, false
+	}
+	if !s.now().Before(e.expires) {
+		delete(s.cache, key)
+		return nil, false
+	}
+	return e, true
+}
+
+func (s *Server) store(key string, e *entry) {
+	if s.TTL <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for k, old := range s.cache {
+		if !now.Before(old.expires) {
+			delete(s.cache, k)
+		}
+	}
+	s.cache[key] = e
+}
+
+func (s *Server) write(w http.ResponseWriter, e *entry, hit bool) {
+	q := e.quota
+	cache := "MISS"
+	if hit {
+		q = s.client.Quota()
+		q.Last = 0
+		cache = "HIT"
+	}
+	h := w.Header()
+	h.Set("Content-Type", "application/json")
+	h.Set(HeaderCache, cache)
+	if q.Known {
+		h.Set(HeaderRequestsRemaining, strconv.Itoa(q.Remaining))
+		h.Set(HeaderRequestsUsed, strconv.Itoa(q.Used))
+		h.Set(HeaderRequestsLast, strconv.Itoa(q.Last))
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(e.body)
+}
+
+// writeError replies in the same shape as the API's own errors
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": err.Error()})
+}
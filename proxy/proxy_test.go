@@ -0,0 +1,255 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package proxy
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"oddsapi"
+	"oddsapi/internal/testserver"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newUpstream(t *testing.T) *testserver.Server {
+	u := testserver.New(t)
+	u.ApiKey = "real-key"
+	return u
+}
+
+func newProxy(t *testing.T, u *testserver.Server) (*Server, *oddsapi.Client) {
+	client, err := oddsapi.NewClient("real-key", 100, oddsapi.SetBaseUrl(u.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(client, time.Minute)
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	consumer, err := oddsapi.NewClient("consumer-key", 100, oddsapi.SetBaseUrl(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, consumer
+}
+
+func TestServer_Cache(t *testing.T) {
+	u := newUpstream(t)
+	s, consumer := newProxy(t, u)
+
+	params := consumer.OddsService.NewOddsParams("basketball_nba")
+	_ = params.SetRegions(oddsapi.RegionUs)
+	for i := 0; i < 3; i++ {
+		odds, resp, err := consumer.OddsService.GetOdds(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(odds) != 1 || odds[0].Id != "event1" {
+			t.Fatalf("unexpected odds %v", odds)
+		}
+		q := resp.Quota()
+		if !q.Known || q.Remaining != 100 {
+			t.Errorf("expected the upstream quota, got %+v", q)
+		}
+		if i > 0 && (resp.Header.Get(HeaderCache) != "HIT" || q.Last != 0) {
+			t.Errorf("expected a cache hit without cost, got %s costing %d", resp.Header.Get(HeaderCache), q.Last)
+		}
+	}
+	if n := u.Hits.Load(); n != 1 {
+		t.Errorf("expected 1 upstream request, got %d", n)
+	}
+
+	event, _, err := consumer.EventOddsService.GetOdds(consumer.EventOddsService.NewParams("basketball_nba", "event1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Id != "event1" || u.Hits.Load() != 2 {
+		t.Errorf("expected a separate upstream request for event odds")
+	}
+
+	s.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if _, _, err = consumer.OddsService.GetOdds(params); err != nil {
+		t.Fatal(err)
+	}
+	if n := u.Hits.Load(); n != 3 {
+		t.Errorf("expected an expired entry to be refetched, got %d upstream requests", n)
+	}
+}
+
+func TestServer_CanonicalKeyRawBody(t *testing.T) {
+	u := newUpstream(t)
+	_, consumer := newProxy(t, u)
+	base := consumer.GetBaseUrl().String()
+
+	for i, regions := range []string{"us,uk", "uk,us"} {
+		resp, err := http.Get(base + "v4/sports/basketball_nba/odds/?apiKey=consumer-key&regions=" + regions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != testserver.Odds {
+			t.Errorf("expected the upstream body unchanged, got %s", body)
+		}
+		if i > 0 && resp.Header.Get(HeaderCache) != "HIT" {
+			t.Errorf("expected regions %s to hit the entry for the same regions in another order", regions)
+		}
+	}
+	if n := u.Hits.Load(); n != 1 {
+		t.Errorf("expected 1 upstream request, got %d", n)
+	}
+}
+
+func TestServer_Coalesce(t *testing.T) {
+	u := newUpstream(t)
+	u.Release = make(chan struct{})
+	_, consumer := newProxy(t, u)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			params := consumer.OddsService.NewOddsParams("basketball_nba")
+			_ = params.SetRegions(oddsapi.RegionUs)
+			if _, _, err := consumer.OddsService.GetOdds(params); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(u.Release)
+	wg.Wait()
+
+	if n := u.Hits.Load(); n != 1 {
+		t.Errorf("expected concurrent requests to share 1 upstream request, got %d", n)
+	}
+}
+
+func TestServer_BadRequest(t *testing.T) {
+	u := newUpstream(t)
+	s, _ := newProxy(t, u)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v4/sports/basketball_nba/odds?regions=mars", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid region, got %d", rec.Code)
+	}
+	if u.Hits.Load() != 0 {
+		t.Error("expected no upstream request for an invalid query")
+	}
+}
+
+func TestServer_Consumers(t *testing.T) {
+	u := newUpstream(t)
+	s, _ := newProxy(t, u)
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	consumers := []*Consumer{
+		{Name: "pricing", Token: "pricing-token", Limit: 2},
+		{Name: "ops", Token: "ops-token", Admin: true},
+	}
+	for _, c := range consumers {
+		if err := s.AddConsumer(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddConsumer(&Consumer{Name: "other", Token: "ops-token"}); err == nil {
+		t.Error("expected an error for a duplicate token")
+	}
+
+	get := func(token, path string) int {
+		resp, err := http.Get(server.URL + path + "&apiKey=" + token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := get("unknown", "/v4/sports/basketball_nba/odds/?regions=us"); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for an unknown token, got %d", code)
+	}
+	if code := get("pricing-token", "/v4/sports/basketball_nba/odds/?regions=us"); code != http.StatusOK {
+		t.Errorf("expected 200, got %d", code)
+	}
+	if code := get("ops-token", "/v4/sports/basketball_nba/odds/?regions=us"); code != http.StatusOK {
+		t.Errorf("expected a cache hit, got %d", code)
+	}
+	if code := get("pricing-token", "/v4/sports/basketball_nba/odds/?regions=us,uk"); code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 once the limit would be exceeded, got %d", code)
+	}
+
+	usage := s.Usage()
+	if len(usage) != 2 {
+		t.Fatalf("expected usage for 2 consumers, got %v", usage)
+	}
+	ops, pricing := usage[0], usage[1]
+	if pricing.Used != 1 || pricing.Requests != 1 || pricing.Rejected != 1 {
+		t.Errorf("expected pricing to be charged the upstream cost, got %+v", pricing)
+	}
+	if ops.Used != 0 || ops.CacheHits != 1 {
+		t.Errorf("expected ops to be served from the cache for free, got %+v", ops)
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/usage?apiKey=pricing-token", nil))
+	var own []Usage
+	if err := json.Unmarshal(rec.Body.Bytes(), &own); err != nil {
+		t.Fatal(err)
+	}
+	if len(own) != 1 || own[0].Name != "pricing" {
+		t.Errorf("expected consumers to only see their own usage, got %v", own)
+	}
+}
+
+func TestServer_ConsumerConcurrentLimit(t *testing.T) {
+	u := newUpstream(t)
+	u.Release = make(chan struct{})
+	s, _ := newProxy(t, u)
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	if err := s.AddConsumer(&Consumer{Name: "pricing", Token: "pricing-token", Limit: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	codes := make(chan int, 2)
+	for _, region := range []string{"us", "uk"} {
+		go func(region string) {
+			resp, err := http.Get(server.URL + "/v4/sports/basketball_nba/odds/?regions=" + region + "&apiKey=pricing-token")
+			if err != nil {
+				t.Error(err)
+				codes <- 0
+				return
+			}
+			_ = resp.Body.Close()
+			codes <- resp.StatusCode
+		}(region)
+	}
+
+	select {
+	case code := <-codes:
+		if code != http.StatusTooManyRequests {
+			t.Errorf("expected the second concurrent request to be rejected, got %d", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected one request to be rejected while the other is in flight")
+	}
+	close(u.Release)
+	if code := <-codes; code != http.StatusOK {
+		t.Errorf("expected 200 for the reserved request, got %d", code)
+	}
+
+	if usage := s.Usage()[0]; usage.Used != 1 || usage.Requests != 1 || usage.Rejected != 1 {
+		t.Errorf("expected the limit to hold under concurrency, got %+v", usage)
+	}
+}
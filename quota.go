@@ -0,0 +1,53 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	headerRequestsRemaining = "X-Requests-Remaining"
+	headerRequestsUsed      = "X-Requests-Used"
+	headerRequestsLast      = "X-Requests-Last"
+)
+
+// Quota is the usage reported by the API on every response.
+type Quota struct {
+	Remaining int
+	Used      int
+
+	// Last is the cost of the request that returned this quota
+	Last int
+
+	// Known is false when the response did not carry quota headers
+	Known     bool
+	UpdatedAt time.Time
+}
+
+func parseQuota(h http.Header) Quota {
+	var q Quota
+	remaining, errRemaining := strconv.ParseFloat(h.Get(headerRequestsRemaining), 64)
+	used, errUsed := strconv.ParseFloat(h.Get(headerRequestsUsed), 64)
+	if errRemaining != nil || errUsed != nil {
+		return q
+	}
+	last, _ := strconv.ParseFloat(h.Get(headerRequestsLast), 64)
+	q.Remaining = int(remaining)
+	q.Used = int(used)
+	q.Last = int(last)
+	q.Known = true
+	q.UpdatedAt = time.Now()
+	return q
+}
+
+// Quota returns the usage reported on this response
+func (r *Response) Quota() Quota {
+	if r == nil || r.Response == nil {
+		return Quota{}
+	}
+	return parseQuota(r.Header)
+}
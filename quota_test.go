@@ -0,0 +1,35 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestResponse_Quota(t *testing.T) {
+	h := make(http.Header)
+	h.Set("x-requests-remaining", "490")
+	h.Set("x-requests-used", "10")
+	h.Set("x-requests-last", "3")
+	r := newResponse(&http.Response{Header: h})
+
+	q := r.Quota()
+	if !q.Known {
+		t.Fatal("expected quota to be known")
+	}
+	if q.Remaining != 490 || q.Used != 10 || q.Last != 3 {
+		t.Errorf("expected 490/10/3, got %d/%d/%d", q.Remaining, q.Used, q.Last)
+	}
+
+	r = newResponse(&http.Response{Header: make(http.Header)})
+	if r.Quota().Known {
+		t.Error("expected quota to be unknown without headers")
+	}
+
+	var nilResponse *Response
+	if nilResponse.Quota().Known {
+		t.Error("expected quota to be unknown for a nil response")
+	}
+}
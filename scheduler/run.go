@@ -0,0 +1,114 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package scheduler
+
+import (
+	"context"
+	"oddsapi"
+	"time"
+)
+
+const (
+	DefaultTick         = 30 * time.Second
+	DefaultEventRefresh = time.Hour
+)
+
+type Result struct {
+	*Poll
+	Odds     *oddsapi.Odds
+	Response *oddsapi.Response
+	Err      error
+	Time     time.Time
+}
+
+// Runner drives a Scheduler with a Client, refreshing each sport's events
+// and fetching odds for every poll as it comes due.
+type Runner struct {
+	Scheduler *Scheduler
+	Client    *oddsapi.Client
+
+	// Tick is how often due polls are checked
+	Tick time.Duration
+
+	// EventRefresh is how often events are listed for each sport. The
+	// events endpoint does not count against the quota.
+	EventRefresh time.Duration
+}
+
+func NewRunner(s *Scheduler, c *oddsapi.Client) *Runner {
+	return &Runner{
+		Scheduler:    s,
+		Client:       c,
+		Tick:         DefaultTick,
+		EventRefresh: DefaultEventRefresh,
+	}
+}
+
+// Start runs until ctx is cancelled, then closes the returned channel.
+// Errors refreshing events and fetching odds are sent as results.
+func (r *Runner) Start(ctx context.Context) <-chan *Result {
+	results := make(chan *Result)
+	go r.run(ctx, results)
+	return results
+}
+
+func (r *Runner) run(ctx context.Context, results chan<- *Result) {
+	defer close(results)
+
+	tick := time.NewTicker(r.Tick)
+	defer tick.Stop()
+
+	var refreshed time.Time
+	for {
+		now := time.Now()
+		if now.Sub(refreshed) >= r.EventRefresh {
+			for _, err := range r.refreshEvents() {
+				if !send(ctx, results, &Result{Err: err, Time: now}) {
+					return
+				}
+			}
+			refreshed = now
+		}
+
+		for _, p := range r.Scheduler.Due(now) {
+			if ctx.Err() != nil {
+				return
+			}
+			data, resp, err := r.Client.EventOddsService.GetOdds(p.Params)
+			if !send(ctx, results, &Result{Poll: p, Odds: data, Response: resp, Err: err, Time: time.Now()}) {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+func (r *Runner) refreshEvents() []error {
+	var errs []error
+	for _, key := range r.Scheduler.Sports() {
+		params := r.Client.EventService.NewEventParams(key)
+		events, _, err := r.Client.EventService.GetEvents(params)
+		if err == nil {
+			err = r.Scheduler.Track(key, events...)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func send(ctx context.Context, results chan<- *Result, r *Result) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case results <- r:
+		return true
+	}
+}
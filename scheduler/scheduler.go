@@ -0,0 +1,218 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package scheduler polls event odds more often as kickoff approaches and
+// spreads a fixed quota budget across sports.
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/time/rate"
+	"oddsapi"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPeriod = 30 * 24 * time.Hour
+
+	// DefaultInterval is used for events further out than every tier
+	DefaultInterval = 12 * time.Hour
+)
+
+// Tier polls every Interval once an event is within Before of kickoff.
+type Tier struct {
+	Before   time.Duration
+	Interval time.Duration
+}
+
+var DefaultTiers = []Tier{
+	{Before: 15 * time.Minute, Interval: 2 * time.Minute},
+	{Before: time.Hour, Interval: 10 * time.Minute},
+	{Before: 6 * time.Hour, Interval: 30 * time.Minute},
+	{Before: 24 * time.Hour, Interval: 2 * time.Hour},
+	{Before: 72 * time.Hour, Interval: 6 * time.Hour},
+}
+
+type sport struct {
+	key     string
+	weight  float64
+	params  *oddsapi.EventOddsParams
+	limiter *rate.Limiter
+}
+
+type tracked struct {
+	sport    *sport
+	event    *oddsapi.Event
+	commence time.Time
+	next     time.Time
+}
+
+// Poll is a single event odds request that is due.
+type Poll struct {
+	Sport  string
+	Event  *oddsapi.Event
+	Params *oddsapi.EventOddsParams
+	Cost   int
+}
+
+type Scheduler struct {
+	// Budget is the quota to spend per Period across every sport. Both are
+	// applied when sports are added.
+	Budget int
+	Period time.Duration
+
+	// Tiers are checked from the closest to kickoff outward
+	Tiers []Tier
+
+	mu     sync.Mutex
+	sports map[string]*sport
+	events map[string]*tracked
+}
+
+func New(budget int) *Scheduler {
+	return &Scheduler{
+		Budget: budget,
+		Period: DefaultPeriod,
+		Tiers:  DefaultTiers,
+		sports: make(map[string]*sport),
+		events: make(map[string]*tracked),
+	}
+}
+
+// AddSport registers a sport with its share of the budget. Params is a
+// template for every event odds request in the sport; its event key is
+// replaced per event.
+func (s *Scheduler) AddSport(key string, weight float64, params *oddsapi.EventOddsParams) error {
+	if key == "" {
+		return errors.New("sport key is blank")
+	}
+	if weight <= 0 {
+		return fmt.Errorf("invalid weight for %s: %v", key, weight)
+	}
+	if params == nil {
+		return fmt.Errorf("no params provided for %s", key)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sports[key] = &sport{key: key, weight: weight, params: params}
+	s.allocate()
+	return nil
+}
+
+// allocate splits the budget by weight. Each sport may spend up to a day's
+// share at once so that kickoff clusters are not starved.
+func (s *Scheduler) allocate() {
+	var total float64
+	for _, sp := range s.sports {
+		total += sp.weight
+	}
+	for _, sp := range s.sports {
+		share := float64(s.Budget) * sp.weight / total
+		perSecond := share / s.Period.Seconds()
+		burst := int(share / (s.Period.Hours() / 24))
+		sp.limiter = rate.NewLimiter(rate.Limit(perSecond), max(burst, sp.params.Cost()))
+	}
+}
+
+// Track adds or updates events for a registered sport. Events are dropped
+// once they commence.
+func (s *Scheduler) Track(sportKey string, events ...*oddsapi.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sp, ok := s.sports[sportKey]
+	if !ok {
+		return fmt.Errorf("sport %s has not been added", sportKey)
+	}
+	for _, e := range events {
+		commence, err := time.Parse(time.RFC3339, e.CommenceTime)
+		if err != nil {
+			return fmt.Errorf("invalid commence time for event %s: %w", e.Id, err)
+		}
+		if t, ok := s.events[e.Id]; ok {
+			t.event = e
+			t.commence = commence
+			continue
+		}
+		s.events[e.Id] = &tracked{sport: sp, event: e, commence: commence}
+	}
+	return nil
+}
+
+// Interval returns how long to wait between polls for an event starting at
+// commence.
+func (s *Scheduler) Interval(commence, now time.Time) time.Duration {
+	until := commence.Sub(now)
+	tiers := append([]Tier(nil), s.Tiers...)
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].Before < tiers[j].Before })
+	for _, t := range tiers {
+		if until <= t.Before {
+			return t.Interval
+		}
+	}
+	return DefaultInterval
+}
+
+// Due returns the polls to make at now and schedules each event's next
+// poll. Events whose sport is over budget are pushed back until the budget
+// allows them.
+func (s *Scheduler) Due(now time.Time) []*Poll {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*tracked
+	for id, t := range s.events {
+		if !now.Before(t.commence) {
+			delete(s.events, id)
+			continue
+		}
+		if !now.Before(t.next) {
+			due = append(due, t)
+		}
+	}
+	// Closest kickoffs get first claim on the budget
+	sort.Slice(due, func(i, j int) bool { return due[i].commence.Before(due[j].commence) })
+
+	var result []*Poll
+	for _, t := range due {
+		cost := t.sport.params.Cost()
+		r := t.sport.limiter.ReserveN(now, cost)
+		if !r.OK() {
+			continue
+		}
+		if delay := r.DelayFrom(now); delay > 0 {
+			r.CancelAt(now)
+			t.next = now.Add(delay)
+			continue
+		}
+
+		params := *t.sport.params
+		params.SportKey = t.sport.key
+		params.EventKey = t.event.Id
+		result = append(result, &Poll{Sport: t.sport.key, Event: t.event, Params: &params, Cost: cost})
+		t.next = now.Add(s.Interval(t.commence, now))
+	}
+	return result
+}
+
+// Sports returns the keys of every registered sport
+func (s *Scheduler) Sports() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.sports))
+	for key := range s.sports {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Len returns the number of events being tracked
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events)
+}
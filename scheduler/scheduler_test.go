@@ -0,0 +1,97 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package scheduler
+
+import (
+	"oddsapi"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func event(id string, commence time.Time) *oddsapi.Event {
+	return &oddsapi.Event{Id: id, CommenceTime: commence.Format(time.RFC3339)}
+}
+
+func TestScheduler_Interval(t *testing.T) {
+	s := New(500)
+	cases := map[time.Duration]time.Duration{
+		5 * time.Minute:  2 * time.Minute,
+		30 * time.Minute: 10 * time.Minute,
+		3 * time.Hour:    30 * time.Minute,
+		12 * time.Hour:   2 * time.Hour,
+		48 * time.Hour:   6 * time.Hour,
+		96 * time.Hour:   DefaultInterval,
+	}
+	for until, expected := range cases {
+		if result := s.Interval(now.Add(until), now); result != expected {
+			t.Errorf("expected %s before kickoff to poll every %s, got %s", until, expected, result)
+		}
+	}
+}
+
+func TestScheduler_Due(t *testing.T) {
+	s := New(30 * 20)
+	params := &oddsapi.EventOddsParams{Region: "us", Markets: "h2h,spreads"}
+	if err := s.AddSport("basketball_nba", 1, params); err != nil {
+		t.Fatal(err)
+	}
+	err := s.Track("basketball_nba",
+		event("soon", now.Add(10*time.Minute)),
+		event("later", now.Add(10*time.Hour)),
+		event("started", now.Add(-time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	polls := s.Due(now)
+	if len(polls) != 2 {
+		t.Fatalf("expected 2 polls, got %d", len(polls))
+	}
+	if polls[0].Event.Id != "soon" || polls[0].Cost != 2 {
+		t.Errorf("expected the closest event first at cost 2, got %s at %d", polls[0].Event.Id, polls[0].Cost)
+	}
+	if polls[0].Params.EventKey != "soon" || polls[0].Params.SportKey != "basketball_nba" || params.EventKey != "" {
+		t.Error("expected params to be copied per event")
+	}
+	if s.Len() != 2 {
+		t.Errorf("expected the started event to be dropped, tracking %d", s.Len())
+	}
+
+	if polls = s.Due(now.Add(time.Minute)); len(polls) != 0 {
+		t.Errorf("expected nothing due after a minute, got %d", len(polls))
+	}
+	if polls = s.Due(now.Add(2 * time.Minute)); len(polls) != 1 || polls[0].Event.Id != "soon" {
+		t.Errorf("expected the close event to be due again after 2 minutes")
+	}
+}
+
+func TestScheduler_DueRespectsBudget(t *testing.T) {
+	// A budget of 30 per 30 days allows 1 request per day
+	s := New(30)
+	if err := s.AddSport("soccer_epl", 1, &oddsapi.EventOddsParams{Region: "uk", Markets: "h2h"}); err != nil {
+		t.Fatal(err)
+	}
+	err := s.Track("soccer_epl",
+		event("a", now.Add(5*time.Minute)),
+		event("b", now.Add(6*time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	polls := s.Due(now)
+	if len(polls) != 1 || polls[0].Event.Id != "a" {
+		t.Fatalf("expected only the first event within budget, got %d polls", len(polls))
+	}
+	if polls = s.Due(now.Add(time.Minute)); len(polls) != 0 {
+		t.Errorf("expected the second event to wait for budget, got %d polls", len(polls))
+	}
+}
+
+func TestScheduler_TrackUnknownSport(t *testing.T) {
+	if err := New(100).Track("unknown", event("a", now)); err == nil {
+		t.Error("expected an error tracking an unregistered sport")
+	}
+}
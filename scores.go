@@ -0,0 +1,133 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type TeamScore struct {
+	Name  string `json:"name" csv:"name"`
+	Score string `json:"score" csv:"score"`
+}
+
+type Score struct {
+	Id           string       `json:"id" csv:"id"`
+	SportKey     string       `json:"sport_key" csv:"sport_key"`
+	SportTitle   string       `json:"sport_title" csv:"sport_title"`
+	CommenceTime string       `json:"commence_time" csv:"commence_time"`
+	Completed    bool         `json:"completed" csv:"completed"`
+	HomeTeam     string       `json:"home_team" csv:"home_team"`
+	AwayTeam     string       `json:"away_team" csv:"away_team"`
+	Scores       []*TeamScore `json:"scores" csv:"scores"`
+	LastUpdate   *string      `json:"last_update" csv:"last_update"`
+}
+
+// Points returns the parsed score for a team
+func (s *Score) Points(team string) (float64, error) {
+	for _, ts := range s.Scores {
+		if ts.Name == team {
+			return strconv.ParseFloat(ts.Score, 64)
+		}
+	}
+	return 0, fmt.Errorf("no score for %s in event %s", team, s.Id)
+}
+
+// Final returns the home and away scores of a completed event
+func (s *Score) Final() (home, away float64, err error) {
+	if !s.Completed {
+		return 0, 0, fmt.Errorf("event %s is not completed", s.Id)
+	}
+	if home, err = s.Points(s.HomeTeam); err != nil {
+		return 0, 0, err
+	}
+	if away, err = s.Points(s.AwayTeam); err != nil {
+		return 0, 0, err
+	}
+	return home, away, nil
+}
+
+type ScoresParams struct {
+	SportKey string `url:"-"`
+	ApiToken string `url:"apiKey"`
+
+	// Optional number of days in the past to return completed events
+	// for, from 1 to 3, or 0 to leave them out. Live and upcoming events
+	// are always returned.
+	DaysFrom int `url:"daysFrom,omitempty"`
+
+	DateFormat DateFormat `url:"dateFormat,omitempty"`
+
+	// Optional event ids passed as a comma-separated string
+	EventIds *string `url:"eventIds,omitempty"`
+}
+
+func (s *ScoresParams) SetDaysFrom(days int) error {
+	if days < 0 || days > 3 {
+		return fmt.Errorf("days from must be between 0 and 3, got %d", days)
+	}
+	s.DaysFrom = days
+	return nil
+}
+
+func (s *ScoresParams) SetEventIds(eventIds ...string) {
+	if eventIds == nil {
+		s.EventIds = nil
+		return
+	}
+	eventStr := strings.Join(eventIds, ",")
+	s.EventIds = &eventStr
+}
+
+func (s *ScoresParams) ValidateDateFormat() {
+	if s.DateFormat == "" || !s.DateFormat.Valid() {
+		s.DateFormat = DefaultDateFormat
+	}
+}
+
+func (s *ScoresParams) ValidateEventIds() {
+	if s.EventIds != nil && *s.EventIds == "" {
+		s.EventIds = nil
+	}
+}
+
+// Cost is the quota this request will use
+func (s *ScoresParams) Cost() int {
+	if s.DaysFrom > 0 {
+		return 2
+	}
+	return 1
+}
+
+func (s *ScoresParams) BuildPath(baseUrl *url.URL) (string, error) {
+	if s.SportKey == "" {
+		return "", errors.New("sports key is blank")
+	}
+	basePath := fmt.Sprintf("v4/sports/%s/scores", s.SportKey)
+	return buildPath(s, basePath, baseUrl, s.ValidateDateFormat, s.ValidateEventIds)
+}
+
+type ScoresService struct {
+	c *Client
+}
+
+func NewScoresService(c *Client) *ScoresService {
+	return &ScoresService{c: c}
+}
+
+func (s *ScoresService) NewScoresParams(sportKey string) *ScoresParams {
+	return &ScoresParams{
+		ApiToken: s.c.apiToken,
+		SportKey: sportKey,
+	}
+}
+
+func (s *ScoresService) GetScores(params *ScoresParams) ([]*Score, *Response, error) {
+	var data []*Score
+	return requestHandler(params, s.c, data)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package oddsapi
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestScoresParams_BuildPath(t *testing.T) {
+	p := &ScoresParams{SportKey: "basketball_nba", ApiToken: "api-key"}
+	if err := p.SetDaysFrom(2); err != nil {
+		t.Fatal(err)
+	}
+	p.SetEventIds("event1", "event2")
+
+	bURL, _ := url.Parse(DefaultBaseUrl)
+	result, err := p.BuildPath(bURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "https://api.the-odds-api.com/v4/sports/basketball_nba/scores?apiKey=api-key&dateFormat=iso&daysFrom=2&eventIds=event1%2Cevent2"
+	if result != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+	if p.Cost() != 2 {
+		t.Errorf("expected cost 2 with days from, got %d", p.Cost())
+	}
+
+	if err = p.SetDaysFrom(4); err == nil {
+		t.Error("expected an error for days from above 3")
+	}
+	if err = p.SetDaysFrom(0); err != nil || p.Cost() != 1 {
+		t.Errorf("expected 0 to leave out completed events, got cost %d, %v", p.Cost(), err)
+	}
+}
+
+func TestScore_Final(t *testing.T) {
+	s := &Score{
+		Id:        "event1",
+		Completed: true,
+		HomeTeam:  "Home",
+		AwayTeam:  "Away",
+		Scores:    []*TeamScore{{Name: "Away", Score: "98"}, {Name: "Home", Score: "104"}},
+	}
+	home, away, err := s.Final()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if home != 104 || away != 98 {
+		t.Errorf("expected 104-98, got %v-%v", home, away)
+	}
+
+	s.Completed = false
+	if _, _, err = s.Final(); err == nil {
+		t.Error("expected an error for an incomplete event")
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package settlement grades bets on h2h, spreads, totals and team_totals
+// markets from the final scores of an event.
+package settlement
+
+import (
+	"fmt"
+	"math"
+	"oddsapi"
+)
+
+type Result string
+
+const (
+	Pending  Result = "pending"
+	Win      Result = "win"
+	Loss     Result = "loss"
+	Push     Result = "push"
+	HalfWin  Result = "half_win"
+	HalfLoss Result = "half_loss"
+)
+
+const (
+	DrawOutcome  = "Draw"
+	OverOutcome  = "Over"
+	UnderOutcome = "Under"
+)
+
+// Profit returns the profit on stake at decimal odds for this result.
+func (r Result) Profit(stake, decimal float64) float64 {
+	switch r {
+	case Win:
+		return stake * (decimal - 1)
+	case HalfWin:
+		return stake * (decimal - 1) / 2
+	case Loss:
+		return -stake
+	case HalfLoss:
+		return -stake / 2
+	}
+	return 0
+}
+
+type Selection struct {
+	EventId string
+	Market  string
+
+	// Outcome is a team name or Draw for h2h and spreads, and Over or
+	// Under for totals and team_totals
+	Outcome string
+
+	// Team is the team of a team_totals selection, the outcome's description
+	Team string
+
+	// Point is required for spreads, totals and team_totals. Quarter
+	// points such as -0.25 or 2.75 are graded as Asian lines.
+	Point *float64
+
+	// ThreeWay is set when the h2h market quotes a Draw, in which case a tie
+	// loses both team selections instead of pushing
+	ThreeWay bool
+}
+
+// Grade settles a selection against the score of the same event. Events
+// that have not completed are Pending.
+func Grade(sel *Selection, score *oddsapi.Score) (Result, error) {
+	if score == nil {
+		return Pending, nil
+	}
+	if sel.EventId != score.Id {
+		return Pending, fmt.Errorf("selection is for event %s but score is for %s", sel.EventId, score.Id)
+	}
+	if !score.Completed {
+		return Pending, nil
+	}
+	home, away, err := score.Final()
+	if err != nil {
+		return Pending, err
+	}
+
+	switch sel.Market {
+	case oddsapi.MarketH2H.String():
+		return gradeH2H(sel, score, home, away)
+	case oddsapi.MarketSpreads.String():
+		margin, err := teamMargin(sel.Outcome, score, home, away)
+		if err != nil {
+			return Pending, err
+		}
+		return gradeSpread(sel, margin)
+	case oddsapi.MarketTotals.String():
+		return gradeTotal(sel, home+away)
+	case oddsapi.MarketTeamTotals.String():
+		switch sel.Team {
+		case score.HomeTeam:
+			return gradeTotal(sel, home)
+		case score.AwayTeam:
+			return gradeTotal(sel, away)
+		}
+		return Pending, fmt.Errorf("team %q is not playing in event %s", sel.Team, score.Id)
+	}
+	return Pending, fmt.Errorf("cannot settle market %s", sel.Market)
+}
+
+func gradeH2H(sel *Selection, score *oddsapi.Score, home, away float64) (Result, error) {
+	if sel.Outcome == DrawOutcome {
+		if home == away {
+			return Win, nil
+		}
+		return Loss, nil
+	}
+	margin, err := teamMargin(sel.Outcome, score, home, away)
+	if err != nil {
+		return Pending, err
+	}
+	if margin == 0 && sel.ThreeWay {
+		return Loss, nil
+	}
+	return compare(margin), nil
+}
+
+func gradeTotal(sel *Selection, total float64) (Result, error) {
+	if sel.Point == nil {
+		return Pending, fmt.Errorf("%s selection needs a point", sel.Market)
+	}
+	switch sel.Outcome {
+	case OverOutcome:
+		return settle(*sel.Point, func(line float64) float64 { return total - line }), nil
+	case UnderOutcome:
+		return settle(*sel.Point, func(line float64) float64 { return line - total }), nil
+	}
+	return Pending, fmt.Errorf("invalid %s outcome %q", sel.Market, sel.Outcome)
+}
+
+func gradeSpread(sel *Selection, margin float64) (Result, error) {
+	if sel.Point == nil {
+		return Pending, fmt.Errorf("%s selection needs a point", sel.Market)
+	}
+	return settle(*sel.Point, func(line float64) float64 { return margin + line }), nil
+}
+
+// settle grades a line where diff returns how far the selection finished
+// ahead, positive being a win. Quarter points split the stake across the
+// two nearest lines.
+func settle(point float64, diff func(line float64) float64) Result {
+	if !quarter(point) {
+		return compare(diff(point))
+	}
+	return combine(compare(diff(point-0.25)), compare(diff(point+0.25)))
+}
+
+func quarter(point float64) bool {
+	frac := math.Abs(point - math.Trunc(point))
+	return math.Abs(frac-0.25) < 1e-9 || math.Abs(frac-0.75) < 1e-9
+}
+
+func combine(a, b Result) Result {
+	if a == b {
+		return a
+	}
+	switch {
+	case a == Win || b == Win:
+		return HalfWin
+	case a == Loss || b == Loss:
+		return HalfLoss
+	}
+	return Push
+}
+
+func teamMargin(team string, score *oddsapi.Score, home, away float64) (float64, error) {
+	switch team {
+	case score.HomeTeam:
+		return home - away, nil
+	case score.AwayTeam:
+		return away - home, nil
+	}
+	return 0, fmt.Errorf("team %q is not playing in event %s", team, score.Id)
+}
+
+func compare(v float64) Result {
+	switch {
+	case v > 0:
+		return Win
+	case v < 0:
+		return Loss
+	}
+	return Push
+}
@@ -0,0 +1,99 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package settlement
+
+import (
+	"oddsapi"
+	"testing"
+)
+
+func point(p float64) *float64 {
+	return &p
+}
+
+func score(sportKey, home, away string) *oddsapi.Score {
+	return &oddsapi.Score{
+		Id:        "event1",
+		SportKey:  sportKey,
+		Completed: true,
+		HomeTeam:  "Home",
+		AwayTeam:  "Away",
+		Scores:    []*oddsapi.TeamScore{{Name: "Home", Score: home}, {Name: "Away", Score: away}},
+	}
+}
+
+func TestGrade(t *testing.T) {
+	cases := []struct {
+		name     string
+		sel      Selection
+		score    *oddsapi.Score
+		expected Result
+	}{
+		{"h2h win", Selection{Market: "h2h", Outcome: "Home"}, score("basketball_nba", "100", "90"), Win},
+		{"h2h loss", Selection{Market: "h2h", Outcome: "Away"}, score("basketball_nba", "100", "90"), Loss},
+		{"h2h tie two-way", Selection{Market: "h2h", Outcome: "Home"}, score("americanfootball_nfl", "20", "20"), Push},
+		{"h2h tie three-way", Selection{Market: "h2h", Outcome: "Home", ThreeWay: true}, score("soccer_epl", "1", "1"), Loss},
+		{"h2h tie three-way rugby", Selection{Market: "h2h", Outcome: "Away", ThreeWay: true}, score("rugbyunion_six_nations", "20", "20"), Loss},
+		{"h2h draw", Selection{Market: "h2h", Outcome: "Draw"}, score("soccer_epl", "1", "1"), Win},
+		{"spread cover", Selection{Market: "spreads", Outcome: "Away", Point: point(3.5)}, score("basketball_nba", "100", "97"), Win},
+		{"spread push", Selection{Market: "spreads", Outcome: "Home", Point: point(-3)}, score("basketball_nba", "100", "97"), Push},
+		{"spread miss", Selection{Market: "spreads", Outcome: "Home", Point: point(-3.5)}, score("basketball_nba", "100", "97"), Loss},
+		{"asian -0.25 draw", Selection{Market: "spreads", Outcome: "Home", Point: point(-0.25)}, score("soccer_epl", "1", "1"), HalfLoss},
+		{"asian +0.25 draw", Selection{Market: "spreads", Outcome: "Away", Point: point(0.25)}, score("soccer_epl", "1", "1"), HalfWin},
+		{"asian -0.75 by one", Selection{Market: "spreads", Outcome: "Home", Point: point(-0.75)}, score("soccer_epl", "2", "1"), HalfWin},
+		{"asian -1.25 by one", Selection{Market: "spreads", Outcome: "Home", Point: point(-1.25)}, score("soccer_epl", "2", "1"), HalfLoss},
+		{"over", Selection{Market: "totals", Outcome: "Over", Point: point(2.5)}, score("soccer_epl", "2", "1"), Win},
+		{"under push", Selection{Market: "totals", Outcome: "Under", Point: point(3)}, score("soccer_epl", "2", "1"), Push},
+		{"asian over 2.25", Selection{Market: "totals", Outcome: "Over", Point: point(2.25)}, score("soccer_epl", "1", "1"), HalfLoss},
+		{"asian under 2.25", Selection{Market: "totals", Outcome: "Under", Point: point(2.25)}, score("soccer_epl", "1", "1"), HalfWin},
+		{"team total over", Selection{Market: "team_totals", Outcome: "Over", Team: "Away", Point: point(95.5)}, score("basketball_nba", "90", "100"), Win},
+		{"team total under", Selection{Market: "team_totals", Outcome: "Under", Team: "Home", Point: point(95.5)}, score("basketball_nba", "90", "100"), Win},
+	}
+	for _, c := range cases {
+		c.sel.EventId = "event1"
+		result, err := Grade(&c.sel, c.score)
+		if err != nil {
+			t.Errorf("%s: %s", c.name, err)
+			continue
+		}
+		if result != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expected, result)
+		}
+	}
+}
+
+func TestGrade_PendingAndErrors(t *testing.T) {
+	s := score("basketball_nba", "100", "90")
+	s.Completed = false
+	result, err := Grade(&Selection{EventId: "event1", Market: "h2h", Outcome: "Home"}, s)
+	if err != nil || result != Pending {
+		t.Errorf("expected pending for an incomplete event, got %s %v", result, err)
+	}
+
+	s.Completed = true
+	if _, err = Grade(&Selection{EventId: "other", Market: "h2h", Outcome: "Home"}, s); err == nil {
+		t.Error("expected an error for a different event")
+	}
+	if _, err = Grade(&Selection{EventId: "event1", Market: "h2h", Outcome: "Nobody"}, s); err == nil {
+		t.Error("expected an error for a team not in the event")
+	}
+	if _, err = Grade(&Selection{EventId: "event1", Market: "spreads", Outcome: "Home"}, s); err == nil {
+		t.Error("expected an error for a spread without a point")
+	}
+	if _, err = Grade(&Selection{EventId: "event1", Market: "outrights", Outcome: "Home"}, s); err == nil {
+		t.Error("expected an error for an unsupported market")
+	}
+}
+
+func TestResult_Profit(t *testing.T) {
+	if p := HalfWin.Profit(10, 1.9); p != 4.5 {
+		t.Errorf("expected half win profit 4.5, got %v", p)
+	}
+	if p := HalfLoss.Profit(10, 1.9); p != -5 {
+		t.Errorf("expected half loss profit -5, got %v", p)
+	}
+	if p := Push.Profit(10, 1.9); p != 0 {
+		t.Errorf("expected push profit 0, got %v", p)
+	}
+}
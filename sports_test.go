@@ -75,7 +75,7 @@ func TestSportsService_GetSports_DoError(t *testing.T) {
 
 	DoFn := func(req *retryablehttp.Request, data interface{}) (*Response, error) {
 		r := &Response{
-			&http.Response{
+			Response: &http.Response{
 				StatusCode: http.StatusBadRequest,
 				Status:     "bad request",
 			},
@@ -0,0 +1,147 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package staking sizes bets with the Kelly criterion from a fair
+// probability and an offered price.
+package staking
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"oddsapi"
+	"sort"
+)
+
+// Kelly returns the fraction of bankroll to stake on a single outcome. It is
+// zero when the price offers no edge over the probability.
+func Kelly(probability, price float64, format oddsapi.OddsFormat) (float64, error) {
+	if probability < 0 || probability > 1 {
+		return 0, fmt.Errorf("invalid probability: %v", probability)
+	}
+	decimal, err := format.ToDecimal(price)
+	if err != nil {
+		return 0, err
+	}
+	f := (probability*decimal - 1) / (decimal - 1)
+	return math.Max(f, 0), nil
+}
+
+// FractionalKelly scales the Kelly fraction by multiplier, e.g. 0.5 for half Kelly.
+func FractionalKelly(probability, price float64, format oddsapi.OddsFormat, multiplier float64) (float64, error) {
+	f, err := Kelly(probability, price, format)
+	if err != nil {
+		return 0, err
+	}
+	return f * multiplier, nil
+}
+
+type Bet struct {
+	Probability float64
+	Price       float64
+}
+
+// SimultaneousKelly returns the Kelly fraction for each of several mutually
+// exclusive outcomes on the same market bet at once, such as every runner
+// in an outright. Outcomes without enough edge are staked zero.
+func SimultaneousKelly(bets []Bet, format oddsapi.OddsFormat) ([]float64, error) {
+	if len(bets) == 0 {
+		return nil, errors.New("no bets provided")
+	}
+
+	type candidate struct {
+		index       int
+		probability float64
+		decimal     float64
+	}
+	candidates := make([]candidate, len(bets))
+	var totalProbability float64
+	for i, b := range bets {
+		if b.Probability < 0 || b.Probability > 1 {
+			return nil, fmt.Errorf("invalid probability: %v", b.Probability)
+		}
+		decimal, err := format.ToDecimal(b.Price)
+		if err != nil {
+			return nil, err
+		}
+		totalProbability += b.Probability
+		candidates[i] = candidate{index: i, probability: b.Probability, decimal: decimal}
+	}
+	if totalProbability > 1+1e-9 {
+		return nil, fmt.Errorf("probabilities of exclusive outcomes sum to %v", totalProbability)
+	}
+
+	// Add outcomes in order of expected return while each still beats the
+	// reserve rate of the outcomes already chosen.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].probability*candidates[i].decimal > candidates[j].probability*candidates[j].decimal
+	})
+	reserve := 1.0
+	var chosen int
+	var probabilities, implied float64
+	for _, c := range candidates {
+		if c.probability*c.decimal <= reserve || implied+1/c.decimal >= 1 {
+			break
+		}
+		probabilities += c.probability
+		implied += 1 / c.decimal
+		reserve = (1 - probabilities) / (1 - implied)
+		chosen++
+	}
+
+	fractions := make([]float64, len(bets))
+	for _, c := range candidates[:chosen] {
+		fractions[c.index] = c.probability - reserve/c.decimal
+	}
+	return fractions, nil
+}
+
+// Sizer turns Kelly fractions into stakes for a bankroll.
+type Sizer struct {
+	Bankroll float64
+
+	// Multiplier applied to full Kelly, e.g. 0.25 for quarter Kelly
+	Multiplier float64
+
+	// MaxFraction caps the stake as a fraction of bankroll. Zero disables.
+	MaxFraction float64
+
+	// MaxStake caps the stake as an amount. Zero disables.
+	MaxStake float64
+}
+
+func NewSizer(bankroll, multiplier float64) *Sizer {
+	return &Sizer{Bankroll: bankroll, Multiplier: multiplier}
+}
+
+func (s *Sizer) Stake(probability, price float64, format oddsapi.OddsFormat) (float64, error) {
+	f, err := FractionalKelly(probability, price, format, s.Multiplier)
+	if err != nil {
+		return 0, err
+	}
+	return s.amount(f), nil
+}
+
+// Stakes sizes mutually exclusive outcomes with SimultaneousKelly.
+func (s *Sizer) Stakes(bets []Bet, format oddsapi.OddsFormat) ([]float64, error) {
+	fractions, err := SimultaneousKelly(bets, format)
+	if err != nil {
+		return nil, err
+	}
+	stakes := make([]float64, len(fractions))
+	for i, f := range fractions {
+		stakes[i] = s.amount(f * s.Multiplier)
+	}
+	return stakes, nil
+}
+
+func (s *Sizer) amount(fraction float64) float64 {
+	if s.MaxFraction > 0 && fraction > s.MaxFraction {
+		fraction = s.MaxFraction
+	}
+	stake := fraction * s.Bankroll
+	if s.MaxStake > 0 && stake > s.MaxStake {
+		stake = s.MaxStake
+	}
+	return stake
+}
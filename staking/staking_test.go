@@ -0,0 +1,97 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package staking
+
+import (
+	"math"
+	"oddsapi"
+	"testing"
+)
+
+func TestKelly(t *testing.T) {
+	f, err := Kelly(0.5, 2.2, oddsapi.DecimalOddsFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(f-0.1/1.2) > 1e-9 {
+		t.Errorf("expected %v, got %v", 0.1/1.2, f)
+	}
+
+	american, err := Kelly(0.5, 120, oddsapi.AmericanOddsFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(american-f) > 1e-9 {
+		t.Errorf("expected american +120 to match decimal 2.2, got %v", american)
+	}
+
+	f, err = Kelly(0.4, 2.2, oddsapi.DecimalOddsFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f != 0 {
+		t.Errorf("expected no stake without an edge, got %v", f)
+	}
+
+	if _, err = Kelly(1.5, 2.2, oddsapi.DecimalOddsFormat); err == nil {
+		t.Error("expected an error for a probability above 1")
+	}
+}
+
+func TestSimultaneousKelly(t *testing.T) {
+	fractions, err := SimultaneousKelly([]Bet{
+		{Probability: 0.5, Price: 2.2},
+		{Probability: 0.5, Price: 1.7},
+	}, oddsapi.DecimalOddsFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	single, _ := Kelly(0.5, 2.2, oddsapi.DecimalOddsFormat)
+	if math.Abs(fractions[0]-single) > 1e-9 || fractions[1] != 0 {
+		t.Errorf("expected only the first outcome staked at %v, got %v", single, fractions)
+	}
+
+	fractions, err = SimultaneousKelly([]Bet{
+		{Probability: 0.2, Price: 6},
+		{Probability: 0.3, Price: 4},
+		{Probability: 0.5, Price: 1.5},
+	}, oddsapi.DecimalOddsFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fractions[0] <= 0 || fractions[1] <= 0 {
+		t.Errorf("expected both value outcomes to be staked, got %v", fractions)
+	}
+	if fractions[2] != 0 {
+		t.Errorf("expected the favourite to be unstaked, got %v", fractions[2])
+	}
+
+	if _, err = SimultaneousKelly([]Bet{{0.6, 2}, {0.6, 2}}, oddsapi.DecimalOddsFormat); err == nil {
+		t.Error("expected an error when probabilities sum above 1")
+	}
+}
+
+func TestSizer(t *testing.T) {
+	s := NewSizer(1000, 0.5)
+	stake, err := s.Stake(0.5, 2.2, oddsapi.DecimalOddsFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := 1000 * 0.5 * 0.1 / 1.2
+	if math.Abs(stake-expected) > 1e-9 {
+		t.Errorf("expected %v, got %v", expected, stake)
+	}
+
+	s.MaxFraction = 0.02
+	stake, _ = s.Stake(0.5, 2.2, oddsapi.DecimalOddsFormat)
+	if math.Abs(stake-20) > 1e-9 {
+		t.Errorf("expected stake capped at 20, got %v", stake)
+	}
+
+	s.MaxStake = 10
+	stake, _ = s.Stake(0.5, 2.2, oddsapi.DecimalOddsFormat)
+	if stake != 10 {
+		t.Errorf("expected stake capped at 10, got %v", stake)
+	}
+}
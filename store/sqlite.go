@@ -0,0 +1,255 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	_ "modernc.org/sqlite"
+	"oddsapi"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS events (
+	id            TEXT PRIMARY KEY,
+	sport_key     TEXT NOT NULL,
+	sport_title   TEXT NOT NULL,
+	commence_time TEXT NOT NULL,
+	home_team     TEXT NOT NULL,
+	away_team     TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS bookmakers (
+	key   TEXT PRIMARY KEY,
+	title TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS snapshots (
+	id         INTEGER PRIMARY KEY,
+	fetched_at INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS markets (
+	id        INTEGER PRIMARY KEY,
+	event_id  TEXT NOT NULL REFERENCES events (id),
+	bookmaker TEXT NOT NULL REFERENCES bookmakers (key),
+	key       TEXT NOT NULL,
+	UNIQUE (event_id, bookmaker, key)
+);
+CREATE TABLE IF NOT EXISTS outcomes (
+	id          INTEGER PRIMARY KEY,
+	market_id   INTEGER NOT NULL REFERENCES markets (id),
+	name        TEXT NOT NULL,
+	description TEXT NOT NULL,
+	point_key   TEXT NOT NULL,
+	point       REAL,
+	last_price  REAL,
+	UNIQUE (market_id, name, description, point_key)
+);
+CREATE TABLE IF NOT EXISTS prices (
+	outcome_id  INTEGER NOT NULL REFERENCES outcomes (id),
+	snapshot_id INTEGER NOT NULL REFERENCES snapshots (id),
+	fetched_at  INTEGER NOT NULL,
+	last_update TEXT NOT NULL,
+	price       REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS prices_outcome_fetched ON prices (outcome_id, fetched_at);
+`
+
+// SQLite is a Store in a single SQLite database file.
+type SQLite struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens or creates the database at path. Use ":memory:" for a
+// throwaway database.
+func OpenSQLite(path string) (*SQLite, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer, and an in-memory database is per connection
+	db.SetMaxOpenConns(1)
+
+	if _, err = db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &SQLite{db: db}, nil
+}
+
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLite) SaveSnapshot(ctx context.Context, odds []*oddsapi.Odds, fetchedAt time.Time) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO snapshots (fetched_at) VALUES (?)`, fetchedAt.UnixNano())
+	if err != nil {
+		return err
+	}
+	snapshotId, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range odds {
+		if e == nil {
+			continue
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO events (id, sport_key, sport_title, commence_time, home_team, away_team)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET commence_time = excluded.commence_time`,
+			e.Id, e.SportKey, e.SportTitle, e.CommenceTime, e.HomeTeam, e.AwayTeam)
+		if err != nil {
+			return err
+		}
+		for _, b := range e.BookMakers {
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO bookmakers (key, title) VALUES (?, ?)
+				ON CONFLICT (key) DO UPDATE SET title = excluded.title`,
+				b.Key, b.Title)
+			if err != nil {
+				return err
+			}
+			for _, m := range b.Markets {
+				if err = saveMarket(ctx, tx, e.Id, b.Key, m, snapshotId, fetchedAt); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func saveMarket(ctx context.Context, tx *sql.Tx, eventId, bookMaker string, m *oddsapi.Market, snapshotId int64, fetchedAt time.Time) error {
+	var marketId int64
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO markets (event_id, bookmaker, key) VALUES (?, ?, ?)
+		ON CONFLICT (event_id, bookmaker, key) DO UPDATE SET key = excluded.key
+		RETURNING id`,
+		eventId, bookMaker, m.Key).Scan(&marketId)
+	if err != nil {
+		return err
+	}
+
+	// Outcomes missing from this snapshot lose their last price, so a line
+	// that moves away and later reverts is recorded again
+	quoted := make([]any, 0, len(m.Outcomes)+1)
+	quoted = append(quoted, marketId)
+	for _, o := range m.Outcomes {
+		var (
+			outcomeId int64
+			lastPrice sql.NullFloat64
+		)
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO outcomes (market_id, name, description, point_key, point) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (market_id, name, description, point_key) DO UPDATE SET name = excluded.name
+			RETURNING id, last_price`,
+			marketId, o.Name, o.Description, pointKey(o.Point), nullPoint(o.Point)).Scan(&outcomeId, &lastPrice)
+		if err != nil {
+			return err
+		}
+		quoted = append(quoted, outcomeId)
+		if lastPrice.Valid && lastPrice.Float64 == o.Price {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO prices (outcome_id, snapshot_id, fetched_at, last_update, price) VALUES (?, ?, ?, ?, ?)`,
+			outcomeId, snapshotId, fetchedAt.UnixNano(), m.LastUpdate, o.Price)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE outcomes SET last_price = ? WHERE id = ?`, o.Price, outcomeId)
+		if err != nil {
+			return err
+		}
+	}
+
+	query := `UPDATE outcomes SET last_price = NULL WHERE market_id = ?`
+	if len(quoted) > 1 {
+		query += ` AND id NOT IN (?` + strings.Repeat(`, ?`, len(quoted)-2) + `)`
+	}
+	_, err = tx.ExecContext(ctx, query, quoted...)
+	return err
+}
+
+func (s *SQLite) PriceHistory(ctx context.Context, q *PriceQuery) ([]*PricePoint, error) {
+	if q.EventId == "" || q.BookMaker == "" || q.Market == "" || q.Outcome == "" {
+		return nil, errors.New("price history needs an event, bookmaker, market and outcome")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`
+		SELECT p.fetched_at, p.last_update, p.price, o.point
+		FROM prices p
+		JOIN outcomes o ON o.id = p.outcome_id
+		JOIN markets m ON m.id = o.market_id
+		WHERE m.event_id = ? AND m.bookmaker = ? AND m.key = ? AND o.name = ? AND o.description = ?`)
+	args := []any{q.EventId, q.BookMaker, q.Market, q.Outcome, q.Description}
+	if q.Point != nil {
+		sb.WriteString(` AND o.point_key = ?`)
+		args = append(args, pointKey(q.Point))
+	}
+	if !q.From.IsZero() {
+		sb.WriteString(` AND p.fetched_at >= ?`)
+		args = append(args, q.From.UnixNano())
+	}
+	if !q.To.IsZero() {
+		sb.WriteString(` AND p.fetched_at <= ?`)
+		args = append(args, q.To.UnixNano())
+	}
+	sb.WriteString(` ORDER BY p.fetched_at, p.rowid`)
+
+	rows, err := s.db.QueryContext(ctx, sb.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []*PricePoint
+	for rows.Next() {
+		var (
+			fetchedAt int64
+			point     sql.NullFloat64
+			p         PricePoint
+		)
+		if err = rows.Scan(&fetchedAt, &p.LastUpdate, &p.Price, &point); err != nil {
+			return nil, err
+		}
+		p.FetchedAt = time.Unix(0, fetchedAt).UTC()
+		if point.Valid {
+			v := point.Float64
+			p.Point = &v
+		}
+		result = append(result, &p)
+	}
+	return result, rows.Err()
+}
+
+func pointKey(point *float64) string {
+	if point == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*point, 'f', -1, 64)
+}
+
+func nullPoint(point *float64) sql.NullFloat64 {
+	if point == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *point, Valid: true}
+}
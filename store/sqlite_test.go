@@ -0,0 +1,138 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+package store
+
+import (
+	"context"
+	"oddsapi"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func point(p float64) *float64 {
+	return &p
+}
+
+func snapshot(homePrice float64, homePoint float64) []*oddsapi.Odds {
+	return []*oddsapi.Odds{{
+		Id:       "event1",
+		SportKey: "basketball_nba",
+		HomeTeam: "Home",
+		AwayTeam: "Away",
+		BookMakers: []*oddsapi.BookMaker{{
+			Key:   "book1",
+			Title: "Book 1",
+			Markets: []*oddsapi.Market{
+				{Key: "h2h", LastUpdate: "2024-01-01T00:00:00Z", Outcomes: []*oddsapi.Outcome{
+					{Name: "Home", Price: 1.9},
+					{Name: "Away", Price: 1.9},
+				}},
+				{Key: "spreads", LastUpdate: "2024-01-01T00:00:00Z", Outcomes: []*oddsapi.Outcome{
+					{Name: "Home", Price: homePrice, Point: point(homePoint)},
+					{Name: "Away", Price: 1.91, Point: point(-homePoint)},
+				}},
+			},
+		}},
+	}}
+}
+
+func TestSQLite_PriceHistory(t *testing.T) {
+	ctx := context.Background()
+	s, err := OpenSQLite(filepath.Join(t.TempDir(), "odds.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := [][]*oddsapi.Odds{
+		snapshot(1.91, -3.5),
+		snapshot(1.91, -3.5),
+		snapshot(1.95, -3.5),
+		snapshot(1.91, -4.5),
+	}
+	for i, odds := range snapshots {
+		if err = s.SaveSnapshot(ctx, odds, start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := s.PriceHistory(ctx, &PriceQuery{EventId: "event1", BookMaker: "book1", Market: "spreads", Outcome: "Home"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 3 price changes, got %d", len(history))
+	}
+	if history[1].Price != 1.95 || !history[1].FetchedAt.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("expected 1.95 at the third snapshot, got %v at %s", history[1].Price, history[1].FetchedAt)
+	}
+	if *history[2].Point != -4.5 {
+		t.Errorf("expected the last change on the -4.5 line, got %v", *history[2].Point)
+	}
+
+	history, err = s.PriceHistory(ctx, &PriceQuery{
+		EventId: "event1", BookMaker: "book1", Market: "spreads", Outcome: "Home",
+		Point: point(-3.5), From: start.Add(time.Minute),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Price != 1.95 {
+		t.Errorf("expected a single change on -3.5 after the first minute, got %d", len(history))
+	}
+
+	history, err = s.PriceHistory(ctx, &PriceQuery{EventId: "event1", BookMaker: "book1", Market: "h2h", Outcome: "Away"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 {
+		t.Errorf("expected unchanged prices to be stored once, got %d", len(history))
+	}
+
+	if _, err = s.PriceHistory(ctx, &PriceQuery{EventId: "event1"}); err == nil {
+		t.Error("expected an error for an incomplete query")
+	}
+}
+
+func TestSQLite_LineReverts(t *testing.T) {
+	ctx := context.Background()
+	s, err := OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := [][]*oddsapi.Odds{
+		snapshot(1.91, -3.5),
+		snapshot(1.91, -4.5),
+		snapshot(1.91, -3.5),
+		snapshot(1.91, -3.5),
+	}
+	for i, odds := range snapshots {
+		if err = s.SaveSnapshot(ctx, odds, start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := s.PriceHistory(ctx, &PriceQuery{EventId: "event1", BookMaker: "book1", Market: "spreads", Outcome: "Home"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{-3.5, -4.5, -3.5}
+	if len(history) != len(expected) {
+		t.Fatalf("expected %d price changes, got %d", len(expected), len(history))
+	}
+	for i, p := range expected {
+		if *history[i].Point != p {
+			t.Errorf("expected change %d on the %v line, got %v", i, p, *history[i].Point)
+		}
+	}
+}
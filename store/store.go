@@ -0,0 +1,47 @@
+// Copyright (c) Paul Schick
+// SPDX-License-Identifier: MPL-2.0
+
+// Package store keeps a durable history of odds responses.
+package store
+
+import (
+	"context"
+	"oddsapi"
+	"time"
+)
+
+type Store interface {
+	// SaveSnapshot records every outcome price in odds as fetched at
+	// fetchedAt. Prices unchanged since they were last saved are skipped.
+	SaveSnapshot(ctx context.Context, odds []*oddsapi.Odds, fetchedAt time.Time) error
+
+	// PriceHistory returns every stored price change for one outcome at
+	// one bookmaker, oldest first.
+	PriceHistory(ctx context.Context, q *PriceQuery) ([]*PricePoint, error)
+
+	Close() error
+}
+
+type PriceQuery struct {
+	EventId   string
+	BookMaker string
+	Market    string
+	Outcome   string
+
+	// Description selects team_totals outcomes by team
+	Description string
+
+	// Point selects a single line. Nil returns every line of the outcome.
+	Point *float64
+
+	// From and To bound the fetch time. Zero values are unbounded.
+	From time.Time
+	To   time.Time
+}
+
+type PricePoint struct {
+	FetchedAt  time.Time
+	LastUpdate string
+	Price      float64
+	Point      *float64
+}